@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"farmer-to-buyer-portal/internal/audit"
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/db"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/tokens"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// cliAuditReason marks audit log entries written by command-line tools
+const cliAuditReason = "command line"
+
+// runCreateAdmin creates a verified admin account
+func runCreateAdmin(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	phone := fs.String("phone", "", "phone number of the new admin (required)")
+	name := fs.String("name", "", "display name of the new admin (required)")
+	password := fs.String("password", "", "password; read from stdin when omitted")
+	fs.Parse(args)
+
+	if *phone == "" || *name == "" {
+		return errors.New("-phone and -name are required")
+	}
+	if *password == "" {
+		var err error
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+	if len(*password) < 6 {
+		return errors.New("password must be at least 6 characters")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	user := models.User{
+		Phone:        *phone,
+		Name:         *name,
+		PasswordHash: string(hashedPassword),
+		Role:         authz.RoleAdmin,
+		IsVerified:   true,
+		IsActive:     true,
+	}
+	err = conn.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("phone = ?", *phone).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("phone number %s is already registered", *phone)
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, audit.Entry{
+			Action:     audit.ActionUserCreateAdmin,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Reason:     cliAuditReason,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created admin %s (%s)\n", user.ID, user.Phone)
+	return nil
+}
+
+// runResetPassword sets a new password for an account, revoking every session
+func runResetPassword(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	phone := fs.String("phone", "", "phone number of the account (required)")
+	password := fs.String("password", "", "new password; a random one is generated when omitted")
+	fs.Parse(args)
+
+	if *phone == "" {
+		return errors.New("-phone is required")
+	}
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = randomPassword(); err != nil {
+			return err
+		}
+	}
+	if len(*password) < 6 {
+		return errors.New("password must be at least 6 characters")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	var user models.User
+	err = conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("phone = ?", *phone).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("no account with phone number %s", *phone)
+			}
+			return err
+		}
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"password_hash":       string(hashedPassword),
+			"token_version":       gorm.Expr("token_version + 1"),
+			"must_reset_password": false,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tokens.RevokeAllSessions(tx, user.ID); err != nil {
+			return err
+		}
+		return audit.Record(tx, audit.Entry{
+			Action:     audit.ActionUserResetPassword,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Reason:     cliAuditReason,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Password reset for %s (%s); all sessions revoked\n", user.ID, user.Phone)
+	if generated {
+		fmt.Printf("New password: %s\n", *password)
+	}
+	return nil
+}
+
+// readPassword reads a password from the first line of stdin
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("no password given on stdin")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// randomPassword returns a random 16 character password
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
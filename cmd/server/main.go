@@ -1,54 +1,64 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"farmer-to-buyer-portal/internal/config"
-	"farmer-to-buyer-portal/internal/db"
-	"farmer-to-buyer-portal/internal/models"
-	"farmer-to-buyer-portal/internal/routes"
-	"farmer-to-buyer-portal/internal/utils"
-
-	"github.com/gin-gonic/gin"
 )
 
-func main() {
-	// Set Gin to debug mode to show routes
-	gin.SetMode(gin.DebugMode)
+const usage = `Usage: server [command] [flags]
 
-	// Load config first to ensure .env is loaded before reading PORT
-	cfg := config.Load()
+Commands:
+  serve             start the HTTP server (default)
+  migrate up        apply pending database migrations
+  migrate down      roll back the latest migrations (requires -yes)
+  migrate status    list applied and pending migrations
+  migrate baseline  mark migrations as applied on a database created before them
+  migrate verify    check that the models match the database schema
+  seed              create demo farmers, buyers and products (development only)
+  reindex           rebuild the product search index
+  load-pincodes     load pincode coordinates from a CSV file
+  create-admin      create an admin account
+  reset-password    set a new password for an account and log it out everywhere
 
-	// Initialize JWT secret
-	utils.InitJWT(cfg)
+Run "server <command> -h" for the flags of a command.
+`
 
-	conn, err := db.Connect(cfg)
-	if err != nil {
-		log.Fatalf("could not start server: %v", err)
-	}
+// commands maps each subcommand to its implementation
+var commands = map[string]func(cfg config.Config, args []string) error{
+	"serve":          runServe,
+	"migrate":        runMigrate,
+	"seed":           runSeed,
+	"reindex":        runReindex,
+	"load-pincodes":  runLoadPincodes,
+	"create-admin":   runCreateAdmin,
+	"reset-password": runResetPassword,
+}
 
-	// Auto-migrate models
-	if err := conn.AutoMigrate(&models.User{}, &models.Product{}, &models.Order{}, &models.OrderItem{}); err != nil {
-		log.Fatalf("failed to migrate database: %v", err)
+func main() {
+	args := os.Args[1:]
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
 	}
 
-	router := routes.SetupRouter(conn)
-
-	// Print registered routes
-	log.Println("INFO: Registered routes:")
-	for _, route := range router.Routes() {
-		log.Printf("  %s %s", route.Method, route.Path)
+	if command == "help" {
+		fmt.Print(usage)
+		return
 	}
-
-	// PORT is loaded from environment (can be set in .env or system env)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	run, ok := commands[command]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
 	}
 
-	log.Printf("INFO: Starting server on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("server stopped unexpectedly: %v", err)
+	// Load config first to ensure .env is loaded before reading PORT
+	cfg := config.Load()
+
+	if err := run(cfg, args); err != nil {
+		log.Fatalf("%s: %v", command, err)
 	}
 }
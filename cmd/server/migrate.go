@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/db"
+	"farmer-to-buyer-portal/internal/db/migrations"
+)
+
+// runMigrate handles the "migrate" subcommands
+func runMigrate(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected a subcommand: up, down, status, baseline or verify")
+	}
+	action, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back (down only)")
+	yes := fs.Bool("yes", false, "confirm rolling back, which can drop tables and data (down only)")
+	version := fs.Int("version", 0, "last migration already reflected in the database (baseline only)")
+	fs.Parse(args)
+
+	switch action {
+	case "up", "status", "verify":
+	case "down":
+		if *steps < 1 {
+			return errors.New("-steps must be at least 1")
+		}
+		if !*yes {
+			return fmt.Errorf("rolling back %d migration(s) can drop tables and data; re-run with -yes to confirm", *steps)
+		}
+	case "baseline":
+		if *version < 1 {
+			return errors.New("-version is required")
+		}
+	default:
+		return fmt.Errorf("unknown subcommand %q, expected up, down, status, baseline or verify", action)
+	}
+
+	conn, err := db.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrations.Up(conn)
+		for _, m := range applied {
+			fmt.Printf("  applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database schema is up to date")
+		}
+
+	case "down":
+		rolledBack, err := migrations.Down(conn, *steps)
+		for _, m := range rolledBack {
+			fmt.Printf("  rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "status":
+		statuses, err := migrations.StatusOf(conn)
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, s := range statuses {
+			if s.AppliedAt == nil {
+				pending++
+				fmt.Printf("  %04d_%-40s pending\n", s.Version, s.Name)
+			} else {
+				fmt.Printf("  %04d_%-40s applied %s\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+			}
+		}
+		if pending > 0 {
+			fmt.Printf("%d pending migration(s); run \"migrate up\" to apply them\n", pending)
+		}
+
+	case "baseline":
+		marked, err := migrations.Baseline(conn, *version)
+		for _, m := range marked {
+			fmt.Printf("  marked %04d_%s as applied\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "verify":
+		drifts, err := db.Verify(conn)
+		if err != nil {
+			return err
+		}
+		for _, d := range drifts {
+			fmt.Printf("  %s\n", d)
+		}
+		if len(drifts) > 0 {
+			return fmt.Errorf("models and database schema differ in %d place(s)", len(drifts))
+		}
+		fmt.Println("Models match the database schema")
+	}
+	return nil
+}
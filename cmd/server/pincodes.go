@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/db"
+	"farmer-to-buyer-portal/internal/geo"
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// runLoadPincodes loads pincode coordinates from a CSV file
+func runLoadPincodes(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("load-pincodes", flag.ExitOnError)
+	file := fs.String("file", cfg.PincodesFile, "CSV with pincode, latitude and longitude columns (default $PINCODES_FILE)")
+	fs.Parse(args)
+
+	if *file == "" {
+		return errors.New("no pincode file given; pass -file or set PINCODES_FILE")
+	}
+
+	conn, err := db.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	summary, err := loadPincodes(conn, *file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Loaded %d pincodes from %d rows (%d rows skipped)\n", summary.Pincodes, summary.Rows, summary.Skipped)
+	return nil
+}
+
+// loadPincodesIfEmpty loads the configured pincode file into an empty pincodes table
+func loadPincodesIfEmpty(conn *gorm.DB, cfg config.Config) error {
+	if cfg.PincodesFile == "" {
+		return nil
+	}
+
+	var count int64
+	if err := conn.Model(&models.Pincode{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	summary, err := loadPincodes(conn, cfg.PincodesFile)
+	if err != nil {
+		return err
+	}
+	log.Printf("INFO: loaded %d pincodes from %s (%d rows skipped)", summary.Pincodes, cfg.PincodesFile, summary.Skipped)
+	return nil
+}
+
+func loadPincodes(conn *gorm.DB, path string) (geo.LoadSummary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return geo.LoadSummary{}, err
+	}
+	defer f.Close()
+
+	summary, err := geo.LoadCSV(conn, f)
+	if err != nil {
+		return summary, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return summary, nil
+}
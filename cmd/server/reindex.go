@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/db"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/search"
+)
+
+// runReindex rebuilds the search documents of every product, e.g. after
+// importing listings directly into the database
+func runReindex(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("reindex", flag.ExitOnError)
+	fs.Parse(args)
+
+	conn, err := db.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err := conn.Model(&models.Product{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if err := search.Reindex(context.Background(), conn, search.NewMySQL(conn)); err != nil {
+		return fmt.Errorf("failed to reindex products: %w", err)
+	}
+
+	fmt.Printf("Reindexed %d products\n", count)
+	return nil
+}
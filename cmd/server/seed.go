@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/db"
+	"farmer-to-buyer-portal/internal/search"
+	"farmer-to-buyer-portal/internal/seed"
+)
+
+// runSeed fills the database with demo data for local development
+func runSeed(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	password := fs.String("password", "password123", "password for every demo account")
+	force := fs.Bool("force", false, "seed even when APP_ENV is not development")
+	fs.Parse(args)
+
+	if !cfg.IsDevelopment() && !*force {
+		return fmt.Errorf("refusing to seed demo data with APP_ENV=%s; set APP_ENV=development or re-run with -force to override", cfg.AppEnv)
+	}
+	if len(*password) < 6 {
+		return errors.New("password must be at least 6 characters")
+	}
+
+	conn, err := db.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	summary, err := seed.Run(conn, *password)
+	if err != nil {
+		return err
+	}
+	if err := search.Reindex(context.Background(), conn, search.NewMySQL(conn)); err != nil {
+		return fmt.Errorf("failed to index demo products for search: %w", err)
+	}
+
+	fmt.Printf("Created %d users and %d products (%d demo users already existed)\n",
+		summary.Users, summary.Products, summary.Skipped)
+	if summary.Users > 0 {
+		fmt.Printf("Demo accounts use the password %q\n", *password)
+	}
+	return nil
+}
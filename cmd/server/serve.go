@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/db"
+	"farmer-to-buyer-portal/internal/db/migrations"
+	"farmer-to-buyer-portal/internal/inventory"
+	"farmer-to-buyer-portal/internal/orderstate"
+	"farmer-to-buyer-portal/internal/otp"
+	"farmer-to-buyer-portal/internal/routes"
+	"farmer-to-buyer-portal/internal/search"
+	"farmer-to-buyer-portal/internal/sms"
+	"farmer-to-buyer-portal/internal/tokens"
+	"farmer-to-buyer-portal/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// runServe starts the HTTP server
+func runServe(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	migrate := fs.Bool("migrate", false, "apply pending database migrations before serving")
+	fs.Parse(args)
+
+	// Set Gin to debug mode to show routes
+	gin.SetMode(gin.DebugMode)
+
+	// Initialize JWT secret and token lifetimes
+	if err := utils.InitJWT(cfg); err != nil {
+		return fmt.Errorf("failed to initialize JWT keys: %w", err)
+	}
+	tokens.Init(cfg)
+
+	// Initialize SMS delivery for one-time codes
+	sender, err := sms.NewSender(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to configure SMS sender: %w", err)
+	}
+	otp.Init(cfg, sender)
+
+	// Initialize stock reservation window
+	inventory.Init(cfg)
+
+	conn, err := db.Connect(cfg)
+	if err != nil {
+		return fmt.Errorf("could not start server: %w", err)
+	}
+
+	// Schema changes run on demand; refuse to serve against an outdated schema
+	if *migrate {
+		applied, err := migrations.Up(conn)
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+		for _, m := range applied {
+			log.Printf("INFO: applied migration %04d_%s", m.Version, m.Name)
+		}
+	} else {
+		pending, err := migrations.Pending(conn)
+		if err != nil {
+			return fmt.Errorf("failed to check migrations: %w", err)
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("database schema has %d pending migration(s); run \"server migrate up\" or start with -migrate", len(pending))
+		}
+	}
+
+	if err := loadPincodesIfEmpty(conn, cfg); err != nil {
+		return fmt.Errorf("failed to load pincodes: %w", err)
+	}
+
+	// Release stock held by pending orders that were never accepted
+	go orderstate.RunSweeper(context.Background(), conn, cfg.ReservationSweepInterval)
+
+	router := routes.SetupRouter(conn, search.NewMySQL(conn))
+
+	// Print registered routes
+	log.Println("INFO: Registered routes:")
+	for _, route := range router.Routes() {
+		log.Printf("  %s %s", route.Method, route.Path)
+	}
+
+	// PORT is loaded from environment (can be set in .env or system env)
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	log.Printf("INFO: Starting server on port %s", port)
+	if err := router.Run(":" + port); err != nil {
+		return fmt.Errorf("server stopped unexpectedly: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Package audit records administrative actions in the audit_logs table.
+package audit
+
+import (
+	"encoding/json"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionUserView               = "user.view"
+	ActionUserViewProducts       = "user.view_products"
+	ActionUserViewOrders         = "user.view_orders"
+	ActionUserActivate           = "user.activate"
+	ActionUserDeactivate         = "user.deactivate"
+	ActionUserVerify             = "user.verify"
+	ActionUserUnverify           = "user.unverify"
+	ActionUserForcePasswordReset = "user.force_password_reset"
+	ActionUserCreateAdmin        = "user.create_admin"
+	ActionUserResetPassword      = "user.reset_password"
+
+	ActionCategoryCreate = "catalog.category_create"
+	ActionCategoryUpdate = "catalog.category_update"
+	ActionCategoryDelete = "catalog.category_delete"
+	ActionCropCreate     = "catalog.crop_create"
+	ActionCropUpdate     = "catalog.crop_update"
+	ActionCropDelete     = "catalog.crop_delete"
+	ActionVarietyAdd     = "catalog.variety_add"
+	ActionVarietyRemove  = "catalog.variety_remove"
+	ActionSynonymAdd     = "catalog.synonym_add"
+	ActionSynonymRemove  = "catalog.synonym_remove"
+)
+
+// Target types
+const (
+	TargetUser     = "user"
+	TargetProduct  = "product"
+	TargetCategory = "crop_category"
+	TargetCrop     = "crop"
+)
+
+// Entry describes one administrative action
+type Entry struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Reason     string
+	Details    map[string]interface{}
+	IPAddress  string
+}
+
+// Record writes entry to the audit log. Call it with the transaction that
+// performs the action so the record and the change commit together.
+func Record(tx *gorm.DB, entry Entry) error {
+	record := models.AuditLog{
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Reason:     entry.Reason,
+		IPAddress:  entry.IPAddress,
+	}
+	if len(entry.Details) > 0 {
+		details, err := json.Marshal(entry.Details)
+		if err != nil {
+			return err
+		}
+		record.Details = string(details)
+	}
+	return tx.Create(&record).Error
+}
@@ -0,0 +1,123 @@
+// Package authz declares which roles exist and what each role may do. Routes
+// are guarded with middleware.RequireRole and middleware.RequirePermission, so
+// handlers never compare role strings themselves.
+package authz
+
+import "sort"
+
+// Roles stored in User.Role
+const (
+	RoleBuyer  = "buyer"
+	RoleFarmer = "farmer"
+	RoleAdmin  = "admin"
+)
+
+// Permission names a single action a role may perform
+type Permission string
+
+// Permissions checked at route registration
+const (
+	// Farmer actions on their own listings and orders
+	ProductsManage      Permission = "products:manage"
+	OrdersFulfil        Permission = "orders:fulfil"
+	ReviewsReply        Permission = "reviews:reply"
+	FarmerProfileManage Permission = "profile:farmer"
+
+	// Buyer actions on their own cart, orders and reviews
+	OrdersPlace        Permission = "orders:place"
+	OrdersCancel       Permission = "orders:cancel"
+	ReviewsWrite       Permission = "reviews:write"
+	BuyerProfileManage Permission = "profile:buyer"
+
+	// OrdersView allows reading an order; buyers and farmers only see their own,
+	// admins see every order
+	OrdersView Permission = "orders:view"
+
+	// Admin moderation
+	UsersManage      Permission = "users:manage"
+	ProductsModerate Permission = "products:moderate"
+	AuditLogView     Permission = "audit:view"
+	CatalogManage    Permission = "catalog:manage"
+)
+
+// descriptions complete the sentence "You do not have permission to ..."
+var descriptions = map[Permission]string{
+	ProductsManage:      "manage products",
+	OrdersFulfil:        "fulfil orders",
+	ReviewsReply:        "reply to reviews",
+	FarmerProfileManage: "manage a farmer profile",
+	OrdersPlace:         "place orders",
+	OrdersCancel:        "cancel orders",
+	ReviewsWrite:        "review orders",
+	BuyerProfileManage:  "manage a buyer profile",
+	OrdersView:          "view orders",
+	UsersManage:         "manage users",
+	ProductsModerate:    "moderate product listings",
+	AuditLogView:        "view the audit log",
+	CatalogManage:       "manage the crop catalog",
+}
+
+// policy is the single source of truth for role permissions. Admins do not
+// inherit buyer or farmer permissions: those act on the caller's own account,
+// which an admin does not have.
+var policy = map[string][]Permission{
+	RoleFarmer: {
+		ProductsManage,
+		OrdersFulfil,
+		OrdersView,
+		ReviewsReply,
+		FarmerProfileManage,
+	},
+	RoleBuyer: {
+		OrdersPlace,
+		OrdersCancel,
+		OrdersView,
+		ReviewsWrite,
+		BuyerProfileManage,
+	},
+	RoleAdmin: {
+		OrdersView,
+		UsersManage,
+		ProductsModerate,
+		AuditLogView,
+		CatalogManage,
+	},
+}
+
+// grants is policy indexed for constant-time lookups
+var grants = func() map[string]map[Permission]bool {
+	index := make(map[string]map[Permission]bool, len(policy))
+	for role, permissions := range policy {
+		index[role] = make(map[Permission]bool, len(permissions))
+		for _, permission := range permissions {
+			index[role][permission] = true
+		}
+	}
+	return index
+}()
+
+// Can reports whether role has permission. Unknown roles have no permissions.
+func Can(role string, permission Permission) bool {
+	return grants[role][permission]
+}
+
+// IsRole reports whether role is one of the known roles
+func IsRole(role string) bool {
+	_, ok := policy[role]
+	return ok
+}
+
+// Permissions returns the permissions granted to role, sorted by name
+func Permissions(role string) []Permission {
+	permissions := append([]Permission(nil), policy[role]...)
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
+
+// Describe returns a human readable description of permission for error messages
+func Describe(permission Permission) string {
+	if description, ok := descriptions[permission]; ok {
+		return description
+	}
+	return string(permission)
+}
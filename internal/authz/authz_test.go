@@ -0,0 +1,64 @@
+package authz
+
+import (
+	"reflect"
+	"testing"
+)
+
+var allPermissions = []Permission{
+	ProductsManage, OrdersFulfil, ReviewsReply, FarmerProfileManage,
+	OrdersPlace, OrdersCancel, ReviewsWrite, BuyerProfileManage,
+	OrdersView,
+	UsersManage, ProductsModerate, AuditLogView, CatalogManage,
+}
+
+// wantPermissions lists each role's permissions sorted by name, as Permissions returns them
+var wantPermissions = map[string][]Permission{
+	RoleFarmer: {OrdersFulfil, OrdersView, ProductsManage, FarmerProfileManage, ReviewsReply},
+	RoleBuyer:  {OrdersCancel, OrdersPlace, OrdersView, BuyerProfileManage, ReviewsWrite},
+	RoleAdmin:  {AuditLogView, CatalogManage, OrdersView, ProductsModerate, UsersManage},
+	"":         nil,
+	"system":   nil,
+}
+
+func TestPermissions(t *testing.T) {
+	for role, want := range wantPermissions {
+		if got := Permissions(role); !reflect.DeepEqual(got, want) {
+			t.Errorf("Permissions(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func TestCan(t *testing.T) {
+	for role, granted := range wantPermissions {
+		has := make(map[Permission]bool, len(granted))
+		for _, p := range granted {
+			has[p] = true
+		}
+		for _, p := range allPermissions {
+			if got := Can(role, p); got != has[p] {
+				t.Errorf("Can(%q, %s) = %v, want %v", role, p, got, has[p])
+			}
+		}
+	}
+}
+
+func TestIsRole(t *testing.T) {
+	for role := range wantPermissions {
+		want := role == RoleFarmer || role == RoleBuyer || role == RoleAdmin
+		if got := IsRole(role); got != want {
+			t.Errorf("IsRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	for _, p := range allPermissions {
+		if got := Describe(p); got == string(p) {
+			t.Errorf("Describe(%s) has no description", p)
+		}
+	}
+	if got := Describe("unknown:thing"); got != "unknown:thing" {
+		t.Errorf("Describe(unknown) = %q, want the permission name", got)
+	}
+}
@@ -0,0 +1,131 @@
+// Package catalog looks up crops in the crop master tables so listings can be
+// linked to a crop and searched by any of its local names.
+package catalog
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Catalog errors
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryInUse    = errors.New("category still has crops")
+	ErrCropNotFound     = errors.New("crop not found")
+	ErrVarietyNotFound  = errors.New("variety not found for this crop")
+	ErrSynonymNotFound  = errors.New("synonym not found")
+	ErrDuplicate        = errors.New("an entry with this name already exists")
+	ErrUnknownLanguage  = errors.New("unknown language code")
+)
+
+// Languages are the ISO 639-1 codes synonyms may be recorded in
+var Languages = map[string]string{
+	"en": "English",
+	"hi": "Hindi",
+	"bn": "Bengali",
+	"gu": "Gujarati",
+	"kn": "Kannada",
+	"ml": "Malayalam",
+	"mr": "Marathi",
+	"or": "Odia",
+	"pa": "Punjabi",
+	"ta": "Tamil",
+	"te": "Telugu",
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns a name such as "Toor Dal" into "toor-dal"
+func Slugify(name string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// NormalizeTerm trims a name and collapses runs of whitespace. Matching is
+// case-insensitive through the table collation.
+func NormalizeTerm(term string) string {
+	return strings.Join(strings.Fields(term), " ")
+}
+
+// MatchCropIDs returns the crops whose name, variety or any synonym contains term
+func MatchCropIDs(db *gorm.DB, term string) ([]string, error) {
+	pattern := "%" + NormalizeTerm(term) + "%"
+
+	var ids []string
+	err := db.Model(&models.Crop{}).
+		Where("name LIKE ?", pattern).
+		Or("id IN (?)", db.Model(&models.CropSynonym{}).Select("crop_id").Where("term LIKE ?", pattern)).
+		Or("id IN (?)", db.Model(&models.CropVariety{}).Select("crop_id").Where("name LIKE ?", pattern)).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Resolve finds the crop called exactly name, by its English name or one of
+// its synonyms. It returns nil when nothing or more than one crop matches.
+func Resolve(db *gorm.DB, name string) (*models.Crop, error) {
+	name = NormalizeTerm(name)
+
+	var crop models.Crop
+	err := db.Where("name = ?", name).First(&crop).Error
+	if err == nil {
+		return &crop, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var ids []string
+	if err := db.Model(&models.CropSynonym{}).Distinct("crop_id").Where("term = ?", name).Pluck("crop_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) != 1 {
+		return nil, nil
+	}
+	if err := db.Where("id = ?", ids[0]).First(&crop).Error; err != nil {
+		return nil, err
+	}
+	return &crop, nil
+}
+
+// FindCrop loads a crop by ID, and the variety when varietyID is set, checking
+// that the variety belongs to the crop
+func FindCrop(db *gorm.DB, cropID, varietyID string) (models.Crop, *models.CropVariety, error) {
+	var crop models.Crop
+	if err := db.Where("id = ?", cropID).First(&crop).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return crop, nil, ErrCropNotFound
+		}
+		return crop, nil, err
+	}
+	if varietyID == "" {
+		return crop, nil, nil
+	}
+
+	var variety models.CropVariety
+	if err := db.Where("id = ? AND crop_id = ?", varietyID, crop.ID).First(&variety).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return crop, nil, ErrVarietyNotFound
+		}
+		return crop, nil, err
+	}
+	return crop, &variety, nil
+}
+
+// CategoryCropIDs returns the IDs of the crops in the category with slug
+func CategoryCropIDs(db *gorm.DB, slug string) ([]string, error) {
+	var category models.CropCategory
+	if err := db.Where("slug = ?", slug).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	var ids []string
+	err := db.Model(&models.Crop{}).Where("category_id = ?", category.ID).Pluck("id", &ids).Error
+	return ids, err
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +16,42 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	JWTSecret  string
+
+	// AppEnv is the deployment environment. It defaults to "production";
+	// "development" must be chosen explicitly and relaxes safety checks.
+	AppEnv string
+
+	// JWTAlgorithm is HS256, RS256 or EdDSA
+	JWTAlgorithm string
+	// JWTPreviousSecrets lists retired HS256 secrets still accepted for verification
+	JWTPreviousSecrets string
+	// JWTKeysDir holds <kid>.pem keys for RS256/EdDSA
+	JWTKeysDir string
+	// JWTActiveKID selects the key in JWTKeysDir used for signing
+	JWTActiveKID string
+
+	// AccessTokenTTL is the lifetime of access tokens
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL is the lifetime of refresh tokens
+	RefreshTokenTTL time.Duration
+
+	// ReservationTTL is how long a pending order holds stock before it is released
+	ReservationTTL time.Duration
+	// ReservationSweepInterval is how often expired stock holds are swept
+	ReservationSweepInterval time.Duration
+
+	// SMSSender selects how SMS messages are delivered: "console" or "file"
+	SMSSender string
+	// SMSFilePath is the outbox file used by the "file" SMS sender
+	SMSFilePath string
+	// OTPTTL is how long a one-time code stays valid
+	OTPTTL time.Duration
+	// OTPResendCooldown is the minimum wait between codes sent to one phone
+	OTPResendCooldown time.Duration
+
+	// PincodesFile is a CSV of pincode coordinates loaded at startup when the
+	// pincodes table is empty; see geo.LoadCSV
+	PincodesFile string
 }
 
 // Load loads configuration from environment variables and optional .env file.
@@ -34,6 +71,26 @@ func Load() Config {
 		DBPassword: getEnv("DB_PASSWORD", ""),
 		DBName:     getEnv("DB_NAME", "farmer_buyer"),
 		JWTSecret:  getEnv("JWT_SECRET", "changeme"),
+
+		AppEnv: getEnv("APP_ENV", "production"),
+
+		JWTAlgorithm:       getEnv("JWT_ALGORITHM", "HS256"),
+		JWTPreviousSecrets: getEnv("JWT_PREVIOUS_SECRETS", ""),
+		JWTKeysDir:         getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKID:       getEnv("JWT_ACTIVE_KID", ""),
+
+		AccessTokenTTL:  getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		ReservationTTL:           getDurationEnv("RESERVATION_TTL", 24*time.Hour),
+		ReservationSweepInterval: getDurationEnv("RESERVATION_SWEEP_INTERVAL", time.Minute),
+
+		SMSSender:         getEnv("SMS_SENDER", "console"),
+		SMSFilePath:       getEnv("SMS_FILE_PATH", "sms_outbox.log"),
+		OTPTTL:            getDurationEnv("OTP_TTL", 5*time.Minute),
+		OTPResendCooldown: getDurationEnv("OTP_RESEND_COOLDOWN", time.Minute),
+
+		PincodesFile: getEnv("PINCODES_FILE", ""),
 	}
 
 	// Log confirmation of loaded DB config (never print password)
@@ -48,9 +105,31 @@ func Load() Config {
 	return cfg
 }
 
+// IsDevelopment reports whether the app runs in a local development environment
+func (c Config) IsDevelopment() bool {
+	switch c.AppEnv {
+	case "development", "dev", "local":
+		return true
+	}
+	return false
+}
+
 func getEnv(key, fallback string) string {
 	if val, ok := os.LookupEnv(key); ok {
 		return val
 	}
 	return fallback
 }
+
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		log.Printf("WARNING: invalid duration %q for %s - using default %s", val, key, fallback)
+		return fallback
+	}
+	return d
+}
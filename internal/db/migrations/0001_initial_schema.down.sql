@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS otp_codes;
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS order_status_events;
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS buyer_profiles;
+DROP TABLE IF EXISTS farmer_profiles;
+DROP TABLE IF EXISTS users;
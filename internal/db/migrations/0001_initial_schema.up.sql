@@ -0,0 +1,233 @@
+-- Initial schema: every table as of the switch from AutoMigrate to versioned migrations.
+-- Databases created earlier by AutoMigrate or schema.sql should be reconciled by hand,
+-- checked with "server migrate verify" and then marked with "server migrate baseline -version 1".
+
+-- Table: users
+CREATE TABLE users (
+    id CHAR(36) PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    role ENUM('farmer', 'buyer', 'admin') NOT NULL,
+    is_verified BOOLEAN DEFAULT FALSE,
+    is_active BOOLEAN DEFAULT TRUE,
+    token_version INT NOT NULL DEFAULT 0,
+    must_reset_password BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at DATETIME(3) NULL,
+    INDEX idx_phone (phone),
+    INDEX idx_role (role),
+    INDEX idx_users_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: farmer_profiles
+CREATE TABLE farmer_profiles (
+    farmer_id CHAR(36) PRIMARY KEY,
+    farm_name VARCHAR(255) NOT NULL,
+    state VARCHAR(100) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    pincode VARCHAR(10) NOT NULL,
+    address TEXT,
+    farm_size_acres DECIMAL(10, 2),
+    rating DECIMAL(3, 2) DEFAULT 0.00,
+    total_orders INT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (farmer_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_pincode (pincode),
+    INDEX idx_farmer_id (farmer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: buyer_profiles
+CREATE TABLE buyer_profiles (
+    buyer_id CHAR(36) PRIMARY KEY,
+    buyer_type ENUM('individual', 'restaurant', 'vendor') NOT NULL,
+    business_name VARCHAR(255),
+    gst_number VARCHAR(50),
+    state VARCHAR(100) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    pincode VARCHAR(10) NOT NULL,
+    address TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_pincode (pincode),
+    INDEX idx_buyer_id (buyer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: products
+CREATE TABLE products (
+    id CHAR(36) PRIMARY KEY,
+    farmer_id CHAR(36) NOT NULL,
+    crop_name VARCHAR(255) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    reserved_quantity DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    unit VARCHAR(50) NOT NULL,
+    price_per_unit DECIMAL(10, 2) NOT NULL,
+    state VARCHAR(100) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    pincode VARCHAR(10) NOT NULL,
+    status ENUM('active', 'closed', 'sold', 'moderated') DEFAULT 'active',
+    moderation_state VARCHAR(20),
+    moderation_reason VARCHAR(500),
+    moderated_by CHAR(36),
+    moderated_at TIMESTAMP NULL,
+    status_before_moderation VARCHAR(20),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (farmer_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_crop_name (crop_name),
+    INDEX idx_pincode (pincode),
+    INDEX idx_farmer_id (farmer_id),
+    INDEX idx_status (status),
+    INDEX idx_moderation_state (moderation_state)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: orders
+CREATE TABLE orders (
+    id CHAR(36) PRIMARY KEY,
+    buyer_id CHAR(36) NOT NULL,
+    farmer_id CHAR(36) NOT NULL,
+    checkout_id CHAR(36),
+    status ENUM('pending', 'accepted', 'rejected', 'shipped', 'delivered', 'cancelled') DEFAULT 'pending',
+    delivery_mode ENUM('pickup', 'courier') NOT NULL,
+    total_amount DECIMAL(10, 2) NOT NULL,
+    cancellation_requested BOOLEAN DEFAULT FALSE,
+    cancellation_reason VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (farmer_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_buyer_id (buyer_id),
+    INDEX idx_farmer_id (farmer_id),
+    INDEX idx_checkout_id (checkout_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: order_items
+CREATE TABLE order_items (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    price_per_unit DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    INDEX idx_order_id (order_id),
+    INDEX idx_product_id (product_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: stock_reservations
+CREATE TABLE stock_reservations (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    status ENUM('held', 'committed', 'released', 'expired') DEFAULT 'held',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    INDEX idx_order_id (order_id),
+    INDEX idx_product_id (product_id),
+    INDEX idx_status (status),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: order_status_events
+CREATE TABLE order_status_events (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    actor_id CHAR(36),
+    actor_role VARCHAR(20) NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    note VARCHAR(500),
+    created_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    INDEX idx_order_id (order_id),
+    INDEX idx_actor_id (actor_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: reviews
+CREATE TABLE reviews (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL UNIQUE,
+    farmer_id CHAR(36) NOT NULL,
+    buyer_id CHAR(36) NOT NULL,
+    rating TINYINT NOT NULL,
+    comment TEXT,
+    reply TEXT,
+    replied_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_farmer_id (farmer_id),
+    INDEX idx_buyer_id (buyer_id),
+    CHECK (rating BETWEEN 1 AND 5)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: otp_codes
+CREATE TABLE otp_codes (
+    id CHAR(36) PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    code_hash VARCHAR(255) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_otp_phone_purpose (phone, purpose),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: sessions
+CREATE TABLE sessions (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    user_agent VARCHAR(255),
+    ip_address VARCHAR(45),
+    last_seen_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: refresh_tokens
+CREATE TABLE refresh_tokens (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    family_id CHAR(36) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    replaced_by CHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id),
+    INDEX idx_family_id (family_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Table: audit_logs
+CREATE TABLE audit_logs (
+    id CHAR(36) PRIMARY KEY,
+    actor_id CHAR(36) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id CHAR(36) NOT NULL,
+    reason VARCHAR(500),
+    details TEXT,
+    ip_address VARCHAR(45),
+    created_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3),
+    INDEX idx_actor_id (actor_id),
+    INDEX idx_action (action),
+    INDEX idx_target_id (target_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,11 @@
+ALTER TABLE orders ADD COLUMN total_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER total_amount_paise;
+UPDATE orders SET total_amount = total_amount_paise / 100;
+ALTER TABLE orders DROP COLUMN total_amount_paise, ALTER COLUMN total_amount DROP DEFAULT;
+
+ALTER TABLE order_items ADD COLUMN price_per_unit DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER price_per_unit_paise;
+UPDATE order_items SET price_per_unit = price_per_unit_paise / 100;
+ALTER TABLE order_items DROP COLUMN price_per_unit_paise, ALTER COLUMN price_per_unit DROP DEFAULT;
+
+ALTER TABLE products ADD COLUMN price_per_unit DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER price_per_unit_paise;
+UPDATE products SET price_per_unit = price_per_unit_paise / 100;
+ALTER TABLE products DROP COLUMN price_per_unit_paise, ALTER COLUMN price_per_unit DROP DEFAULT;
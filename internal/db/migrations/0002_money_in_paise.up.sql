@@ -0,0 +1,14 @@
+-- Store money as integer paise instead of DECIMAL rupees.
+-- Existing values are converted with ROUND, which rounds halves away from zero.
+
+ALTER TABLE products ADD COLUMN price_per_unit_paise BIGINT NOT NULL DEFAULT 0 AFTER price_per_unit;
+UPDATE products SET price_per_unit_paise = ROUND(price_per_unit * 100);
+ALTER TABLE products DROP COLUMN price_per_unit, ALTER COLUMN price_per_unit_paise DROP DEFAULT;
+
+ALTER TABLE order_items ADD COLUMN price_per_unit_paise BIGINT NOT NULL DEFAULT 0 AFTER price_per_unit;
+UPDATE order_items SET price_per_unit_paise = ROUND(price_per_unit * 100);
+ALTER TABLE order_items DROP COLUMN price_per_unit, ALTER COLUMN price_per_unit_paise DROP DEFAULT;
+
+ALTER TABLE orders ADD COLUMN total_amount_paise BIGINT NOT NULL DEFAULT 0 AFTER total_amount;
+UPDATE orders SET total_amount_paise = ROUND(total_amount * 100);
+ALTER TABLE orders DROP COLUMN total_amount, ALTER COLUMN total_amount_paise DROP DEFAULT;
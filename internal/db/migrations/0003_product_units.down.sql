@@ -0,0 +1,2 @@
+-- Unit codes are not reverted to the text farmers originally typed.
+ALTER TABLE products DROP INDEX idx_products_price_per_kg, DROP COLUMN price_per_kg_paise;
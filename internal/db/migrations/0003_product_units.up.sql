@@ -0,0 +1,20 @@
+-- Normalise free-text product units to the codes in package units and store
+-- a price per kg for listings sold by mass. Units outside the catalog are left
+-- as they are and keep a NULL price per kg until the farmer edits the listing.
+
+UPDATE products SET unit = 'kg' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('kg', 'kgs', 'kilo', 'kilos', 'kilogram', 'kilograms');
+UPDATE products SET unit = 'g' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('g', 'gm', 'gms', 'gram', 'grams');
+UPDATE products SET unit = 'quintal' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('quintal', 'quintals', 'qtl', 'qtls');
+UPDATE products SET unit = 'tonne' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('tonne', 'tonnes', 'ton', 'tons', 'mt');
+UPDATE products SET unit = 'piece' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('piece', 'pieces', 'pc', 'pcs', 'nos', 'no');
+UPDATE products SET unit = 'dozen' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('dozen', 'dozens', 'doz', 'dz');
+UPDATE products SET unit = 'litre' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('litre', 'litres', 'liter', 'liters', 'l', 'ltr', 'ltrs');
+UPDATE products SET unit = 'ml' WHERE LOWER(TRIM(TRAILING '.' FROM TRIM(unit))) IN ('ml', 'millilitre', 'millilitres', 'milliliter', 'milliliters');
+
+ALTER TABLE products ADD COLUMN price_per_kg_paise BIGINT NULL AFTER price_per_unit_paise,
+    ADD INDEX idx_products_price_per_kg (price_per_kg_paise);
+
+UPDATE products SET price_per_kg_paise = price_per_unit_paise WHERE unit = 'kg';
+UPDATE products SET price_per_kg_paise = price_per_unit_paise * 1000 WHERE unit = 'g';
+UPDATE products SET price_per_kg_paise = ROUND(price_per_unit_paise / 100) WHERE unit = 'quintal';
+UPDATE products SET price_per_kg_paise = ROUND(price_per_unit_paise / 1000) WHERE unit = 'tonne';
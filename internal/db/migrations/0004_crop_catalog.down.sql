@@ -0,0 +1,8 @@
+ALTER TABLE products DROP FOREIGN KEY fk_products_crop, DROP FOREIGN KEY fk_products_variety;
+ALTER TABLE products DROP INDEX idx_products_crop_id, DROP INDEX idx_products_variety_id,
+    DROP COLUMN crop_id, DROP COLUMN variety_id;
+
+DROP TABLE IF EXISTS crop_synonyms;
+DROP TABLE IF EXISTS crop_varieties;
+DROP TABLE IF EXISTS crops;
+DROP TABLE IF EXISTS crop_categories;
@@ -0,0 +1,750 @@
+-- Crop catalog: categories, crops, varieties and local-language synonyms.
+-- Products link to a crop through crop_id; crop_name stays as the farmer typed it.
+
+CREATE TABLE crop_categories (
+    id CHAR(36) PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_crop_categories_slug (slug)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE crops (
+    id CHAR(36) PRIMARY KEY,
+    category_id CHAR(36) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (category_id) REFERENCES crop_categories(id) ON DELETE RESTRICT,
+    UNIQUE INDEX idx_crops_slug (slug),
+    UNIQUE INDEX idx_crops_name (name),
+    INDEX idx_crops_category_id (category_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE crop_varieties (
+    id CHAR(36) PRIMARY KEY,
+    crop_id CHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (crop_id) REFERENCES crops(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_crop_variety (crop_id, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE crop_synonyms (
+    id CHAR(36) PRIMARY KEY,
+    crop_id CHAR(36) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    term VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (crop_id) REFERENCES crops(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_crop_synonym (crop_id, language, term),
+    INDEX idx_crop_synonyms_term (term)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE products ADD COLUMN crop_id CHAR(36) NULL AFTER crop_name,
+    ADD COLUMN variety_id CHAR(36) NULL AFTER crop_id,
+    ADD INDEX idx_products_crop_id (crop_id),
+    ADD INDEX idx_products_variety_id (variety_id),
+    ADD CONSTRAINT fk_products_crop FOREIGN KEY (crop_id) REFERENCES crops(id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_products_variety FOREIGN KEY (variety_id) REFERENCES crop_varieties(id) ON DELETE SET NULL;
+
+INSERT INTO crop_categories (id, slug, name) VALUES
+    (UUID(), 'vegetables', 'Vegetables'),
+    (UUID(), 'fruits', 'Fruits'),
+    (UUID(), 'grains', 'Grains'),
+    (UUID(), 'pulses', 'Pulses'),
+    (UUID(), 'spices', 'Spices');
+
+INSERT INTO crops (id, category_id, slug, name)
+SELECT UUID(), cc.id, c.slug, c.name
+FROM (
+    SELECT 'tomato' AS slug, 'Tomato' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'onion' AS slug, 'Onion' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'potato' AS slug, 'Potato' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'Brinjal' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'okra' AS slug, 'Okra' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'cabbage' AS slug, 'Cabbage' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'cauliflower' AS slug, 'Cauliflower' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'Green Chilli' AS name, 'vegetables' AS category
+    UNION ALL
+    SELECT 'banana' AS slug, 'Banana' AS name, 'fruits' AS category
+    UNION ALL
+    SELECT 'mango' AS slug, 'Mango' AS name, 'fruits' AS category
+    UNION ALL
+    SELECT 'grapes' AS slug, 'Grapes' AS name, 'fruits' AS category
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'Pomegranate' AS name, 'fruits' AS category
+    UNION ALL
+    SELECT 'coconut' AS slug, 'Coconut' AS name, 'fruits' AS category
+    UNION ALL
+    SELECT 'rice' AS slug, 'Rice' AS name, 'grains' AS category
+    UNION ALL
+    SELECT 'wheat' AS slug, 'Wheat' AS name, 'grains' AS category
+    UNION ALL
+    SELECT 'ragi' AS slug, 'Ragi' AS name, 'grains' AS category
+    UNION ALL
+    SELECT 'maize' AS slug, 'Maize' AS name, 'grains' AS category
+    UNION ALL
+    SELECT 'jowar' AS slug, 'Jowar' AS name, 'grains' AS category
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'Toor Dal' AS name, 'pulses' AS category
+    UNION ALL
+    SELECT 'chana' AS slug, 'Chana' AS name, 'pulses' AS category
+    UNION ALL
+    SELECT 'moong' AS slug, 'Moong' AS name, 'pulses' AS category
+    UNION ALL
+    SELECT 'urad' AS slug, 'Urad' AS name, 'pulses' AS category
+    UNION ALL
+    SELECT 'masoor' AS slug, 'Masoor' AS name, 'pulses' AS category
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'Turmeric' AS name, 'spices' AS category
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'Red Chilli' AS name, 'spices' AS category
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'Cardamom' AS name, 'spices' AS category
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'Black Pepper' AS name, 'spices' AS category
+    UNION ALL
+    SELECT 'ginger' AS slug, 'Ginger' AS name, 'spices' AS category
+    UNION ALL
+    SELECT 'coriander' AS slug, 'Coriander' AS name, 'spices' AS category
+) c
+JOIN crop_categories cc ON cc.slug = c.category;
+
+INSERT INTO crop_varieties (id, crop_id, name)
+SELECT UUID(), c.id, v.name
+FROM (
+    SELECT 'tomato' AS slug, 'Hybrid' AS name
+    UNION ALL
+    SELECT 'tomato' AS slug, 'Desi' AS name
+    UNION ALL
+    SELECT 'onion' AS slug, 'Nashik Red' AS name
+    UNION ALL
+    SELECT 'onion' AS slug, 'Bellary Red' AS name
+    UNION ALL
+    SELECT 'onion' AS slug, 'Small Onion' AS name
+    UNION ALL
+    SELECT 'potato' AS slug, 'Kufri Jyoti' AS name
+    UNION ALL
+    SELECT 'potato' AS slug, 'Kufri Chipsona' AS name
+    UNION ALL
+    SELECT 'banana' AS slug, 'Robusta' AS name
+    UNION ALL
+    SELECT 'banana' AS slug, 'Nendran' AS name
+    UNION ALL
+    SELECT 'banana' AS slug, 'Poovan' AS name
+    UNION ALL
+    SELECT 'banana' AS slug, 'Yelakki' AS name
+    UNION ALL
+    SELECT 'banana' AS slug, 'Grand Naine' AS name
+    UNION ALL
+    SELECT 'mango' AS slug, 'Alphonso' AS name
+    UNION ALL
+    SELECT 'mango' AS slug, 'Banganapalli' AS name
+    UNION ALL
+    SELECT 'mango' AS slug, 'Kesar' AS name
+    UNION ALL
+    SELECT 'mango' AS slug, 'Dasheri' AS name
+    UNION ALL
+    SELECT 'mango' AS slug, 'Totapuri' AS name
+    UNION ALL
+    SELECT 'mango' AS slug, 'Langra' AS name
+    UNION ALL
+    SELECT 'grapes' AS slug, 'Thompson Seedless' AS name
+    UNION ALL
+    SELECT 'grapes' AS slug, 'Sharad Seedless' AS name
+    UNION ALL
+    SELECT 'grapes' AS slug, 'Bangalore Blue' AS name
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'Bhagwa' AS name
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'Ganesh' AS name
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'Arakta' AS name
+    UNION ALL
+    SELECT 'coconut' AS slug, 'Tender' AS name
+    UNION ALL
+    SELECT 'coconut' AS slug, 'Mature' AS name
+    UNION ALL
+    SELECT 'rice' AS slug, 'Basmati' AS name
+    UNION ALL
+    SELECT 'rice' AS slug, 'Sona Masuri' AS name
+    UNION ALL
+    SELECT 'rice' AS slug, 'Ponni' AS name
+    UNION ALL
+    SELECT 'rice' AS slug, 'IR 64' AS name
+    UNION ALL
+    SELECT 'wheat' AS slug, 'Sharbati' AS name
+    UNION ALL
+    SELECT 'wheat' AS slug, 'Lokwan' AS name
+    UNION ALL
+    SELECT 'wheat' AS slug, 'HD 2967' AS name
+    UNION ALL
+    SELECT 'maize' AS slug, 'Sweet Corn' AS name
+    UNION ALL
+    SELECT 'chana' AS slug, 'Kabuli' AS name
+    UNION ALL
+    SELECT 'chana' AS slug, 'Desi' AS name
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'Salem' AS name
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'Erode' AS name
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'Lakadong' AS name
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'Guntur Sannam' AS name
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'Byadgi' AS name
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'Teja' AS name
+) v
+JOIN crops c ON c.slug = v.slug;
+
+INSERT INTO crop_synonyms (id, crop_id, language, term)
+SELECT UUID(), c.id, s.language, s.term
+FROM (
+    SELECT 'tomato' AS slug, 'hi' AS language, 'टमाटर' AS term
+    UNION ALL
+    SELECT 'tomato' AS slug, 'hi' AS language, 'Tamatar' AS term
+    UNION ALL
+    SELECT 'tomato' AS slug, 'ta' AS language, 'தக்காளி' AS term
+    UNION ALL
+    SELECT 'tomato' AS slug, 'ta' AS language, 'Thakkali' AS term
+    UNION ALL
+    SELECT 'tomato' AS slug, 'te' AS language, 'టమాటా' AS term
+    UNION ALL
+    SELECT 'tomato' AS slug, 'te' AS language, 'Tamata' AS term
+    UNION ALL
+    SELECT 'tomato' AS slug, 'kn' AS language, 'ಟೊಮೆಟೊ' AS term
+    UNION ALL
+    SELECT 'tomato' AS slug, 'mr' AS language, 'टोमॅटो' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'hi' AS language, 'प्याज' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'hi' AS language, 'Pyaz' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'hi' AS language, 'Pyaaz' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'ta' AS language, 'வெங்காயம்' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'ta' AS language, 'Vengayam' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'te' AS language, 'ఉల్లిపాయ' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'te' AS language, 'Ullipaya' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'kn' AS language, 'ಈರುಳ್ಳಿ' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'kn' AS language, 'Eerulli' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'mr' AS language, 'कांदा' AS term
+    UNION ALL
+    SELECT 'onion' AS slug, 'mr' AS language, 'Kanda' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'hi' AS language, 'आलू' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'hi' AS language, 'Aloo' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'ta' AS language, 'உருளைக்கிழங்கு' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'ta' AS language, 'Urulaikizhangu' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'te' AS language, 'బంగాళదుంప' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'te' AS language, 'Bangaladumpa' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'kn' AS language, 'ಆಲೂಗಡ್ಡೆ' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'kn' AS language, 'Alugadde' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'mr' AS language, 'बटाटा' AS term
+    UNION ALL
+    SELECT 'potato' AS slug, 'mr' AS language, 'Batata' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'en' AS language, 'Eggplant' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'en' AS language, 'Aubergine' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'hi' AS language, 'बैंगन' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'hi' AS language, 'Baingan' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'ta' AS language, 'கத்தரிக்காய்' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'ta' AS language, 'Kathirikai' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'te' AS language, 'వంకాయ' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'te' AS language, 'Vankaya' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'kn' AS language, 'ಬದನೆಕಾಯಿ' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'kn' AS language, 'Badanekai' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'mr' AS language, 'वांगी' AS term
+    UNION ALL
+    SELECT 'brinjal' AS slug, 'mr' AS language, 'Vangi' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'en' AS language, 'Lady Finger' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'en' AS language, 'Ladies Finger' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'hi' AS language, 'भिंडी' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'hi' AS language, 'Bhindi' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'ta' AS language, 'வெண்டைக்காய்' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'ta' AS language, 'Vendakkai' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'te' AS language, 'బెండకాయ' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'te' AS language, 'Bendakaya' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'kn' AS language, 'ಬೆಂಡೆಕಾಯಿ' AS term
+    UNION ALL
+    SELECT 'okra' AS slug, 'kn' AS language, 'Bendekai' AS term
+    UNION ALL
+    SELECT 'cabbage' AS slug, 'hi' AS language, 'पत्ता गोभी' AS term
+    UNION ALL
+    SELECT 'cabbage' AS slug, 'hi' AS language, 'Patta Gobhi' AS term
+    UNION ALL
+    SELECT 'cabbage' AS slug, 'ta' AS language, 'முட்டைக்கோஸ்' AS term
+    UNION ALL
+    SELECT 'cabbage' AS slug, 'ta' AS language, 'Muttaikose' AS term
+    UNION ALL
+    SELECT 'cabbage' AS slug, 'te' AS language, 'క్యాబేజీ' AS term
+    UNION ALL
+    SELECT 'cauliflower' AS slug, 'hi' AS language, 'फूल गोभी' AS term
+    UNION ALL
+    SELECT 'cauliflower' AS slug, 'hi' AS language, 'Phool Gobhi' AS term
+    UNION ALL
+    SELECT 'cauliflower' AS slug, 'hi' AS language, 'Gobhi' AS term
+    UNION ALL
+    SELECT 'cauliflower' AS slug, 'ta' AS language, 'காலிஃபிளவர்' AS term
+    UNION ALL
+    SELECT 'cauliflower' AS slug, 'te' AS language, 'కాలీఫ్లవర్' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'en' AS language, 'Green Chili' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'hi' AS language, 'हरी मिर्च' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'hi' AS language, 'Hari Mirch' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'ta' AS language, 'பச்சை மிளகாய்' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'ta' AS language, 'Pachai Milagai' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'te' AS language, 'పచ్చి మిరపకాయ' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'te' AS language, 'Pachi Mirapakaya' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'kn' AS language, 'ಹಸಿ ಮೆಣಸಿನಕಾಯಿ' AS term
+    UNION ALL
+    SELECT 'green-chilli' AS slug, 'kn' AS language, 'Hasi Menasinakai' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'hi' AS language, 'केला' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'hi' AS language, 'Kela' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'ta' AS language, 'வாழைப்பழம்' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'ta' AS language, 'Vazhaipazham' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'te' AS language, 'అరటిపండు' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'te' AS language, 'Arati Pandu' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'kn' AS language, 'ಬಾಳೆಹಣ್ಣು' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'kn' AS language, 'Balehannu' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'mr' AS language, 'केळी' AS term
+    UNION ALL
+    SELECT 'banana' AS slug, 'mr' AS language, 'Keli' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'hi' AS language, 'आम' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'hi' AS language, 'Aam' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'ta' AS language, 'மாம்பழம்' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'ta' AS language, 'Mambazham' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'te' AS language, 'మామిడి' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'te' AS language, 'Mamidi' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'kn' AS language, 'ಮಾವು' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'kn' AS language, 'Mavu' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'mr' AS language, 'आंबा' AS term
+    UNION ALL
+    SELECT 'mango' AS slug, 'mr' AS language, 'Amba' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'en' AS language, 'Grape' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'hi' AS language, 'अंगूर' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'hi' AS language, 'Angoor' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'ta' AS language, 'திராட்சை' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'ta' AS language, 'Thiratchai' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'te' AS language, 'ద్రాక్ష' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'te' AS language, 'Draksha' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'kn' AS language, 'ದ್ರಾಕ್ಷಿ' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'kn' AS language, 'Drakshi' AS term
+    UNION ALL
+    SELECT 'grapes' AS slug, 'mr' AS language, 'द्राक्ष' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'hi' AS language, 'अनार' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'hi' AS language, 'Anar' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'ta' AS language, 'மாதுளை' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'ta' AS language, 'Mathulai' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'te' AS language, 'దానిమ్మ' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'te' AS language, 'Danimma' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'kn' AS language, 'ದಾಳಿಂಬೆ' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'kn' AS language, 'Dalimbe' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'mr' AS language, 'डाळिंब' AS term
+    UNION ALL
+    SELECT 'pomegranate' AS slug, 'mr' AS language, 'Dalimb' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'hi' AS language, 'नारियल' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'hi' AS language, 'Nariyal' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'ta' AS language, 'தேங்காய்' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'ta' AS language, 'Thengai' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'te' AS language, 'కొబ్బరి' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'te' AS language, 'Kobbari' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'kn' AS language, 'ತೆಂಗಿನಕಾಯಿ' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'kn' AS language, 'Tenginakai' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'ml' AS language, 'തേങ്ങ' AS term
+    UNION ALL
+    SELECT 'coconut' AS slug, 'ml' AS language, 'Thenga' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'en' AS language, 'Paddy' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'hi' AS language, 'चावल' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'hi' AS language, 'Chawal' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'hi' AS language, 'धान' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'hi' AS language, 'Dhan' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'ta' AS language, 'அரிசி' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'ta' AS language, 'Arisi' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'ta' AS language, 'நெல்' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'ta' AS language, 'Nel' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'te' AS language, 'బియ్యం' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'te' AS language, 'Biyyam' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'kn' AS language, 'ಅಕ್ಕಿ' AS term
+    UNION ALL
+    SELECT 'rice' AS slug, 'kn' AS language, 'Akki' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'hi' AS language, 'गेहूं' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'hi' AS language, 'Gehun' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'ta' AS language, 'கோதுமை' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'ta' AS language, 'Godhumai' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'te' AS language, 'గోధుమ' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'te' AS language, 'Godhuma' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'kn' AS language, 'ಗೋಧಿ' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'kn' AS language, 'Godhi' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'mr' AS language, 'गहू' AS term
+    UNION ALL
+    SELECT 'wheat' AS slug, 'mr' AS language, 'Gahu' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'en' AS language, 'Finger Millet' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'hi' AS language, 'मंडुआ' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'hi' AS language, 'Mandua' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'ta' AS language, 'கேழ்வரகு' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'ta' AS language, 'Kezhvaragu' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'te' AS language, 'రాగులు' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'te' AS language, 'Ragulu' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'kn' AS language, 'ರಾಗಿ' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'mr' AS language, 'नाचणी' AS term
+    UNION ALL
+    SELECT 'ragi' AS slug, 'mr' AS language, 'Nachni' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'en' AS language, 'Corn' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'hi' AS language, 'मक्का' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'hi' AS language, 'Makka' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'ta' AS language, 'மக்காச்சோளம்' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'ta' AS language, 'Makkacholam' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'te' AS language, 'మొక్కజొన్న' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'te' AS language, 'Mokkajonna' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'kn' AS language, 'ಮೆಕ್ಕೆಜೋಳ' AS term
+    UNION ALL
+    SELECT 'maize' AS slug, 'kn' AS language, 'Mekkejola' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'en' AS language, 'Sorghum' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'hi' AS language, 'ज्वार' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'ta' AS language, 'சோளம்' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'ta' AS language, 'Cholam' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'te' AS language, 'జొన్నలు' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'te' AS language, 'Jonnalu' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'kn' AS language, 'ಜೋಳ' AS term
+    UNION ALL
+    SELECT 'jowar' AS slug, 'kn' AS language, 'Jola' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'en' AS language, 'Pigeon Pea' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'en' AS language, 'Arhar' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'hi' AS language, 'अरहर' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'hi' AS language, 'तूर' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'hi' AS language, 'Toor' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'ta' AS language, 'துவரம் பருப்பு' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'ta' AS language, 'Thuvaram Paruppu' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'te' AS language, 'కందిపప్పు' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'te' AS language, 'Kandi Pappu' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'kn' AS language, 'ತೊಗರಿ ಬೇಳೆ' AS term
+    UNION ALL
+    SELECT 'toor-dal' AS slug, 'kn' AS language, 'Togari Bele' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'en' AS language, 'Chickpea' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'en' AS language, 'Bengal Gram' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'hi' AS language, 'चना' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'ta' AS language, 'கொண்டைக்கடலை' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'ta' AS language, 'Kondakadalai' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'te' AS language, 'శనగలు' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'te' AS language, 'Senagalu' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'kn' AS language, 'ಕಡಲೆ' AS term
+    UNION ALL
+    SELECT 'chana' AS slug, 'kn' AS language, 'Kadale' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'en' AS language, 'Green Gram' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'hi' AS language, 'मूंग' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'ta' AS language, 'பாசிப்பயறு' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'ta' AS language, 'Pasi Payaru' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'te' AS language, 'పెసలు' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'te' AS language, 'Pesalu' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'kn' AS language, 'ಹೆಸರು' AS term
+    UNION ALL
+    SELECT 'moong' AS slug, 'kn' AS language, 'Hesaru' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'en' AS language, 'Black Gram' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'hi' AS language, 'उड़द' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'ta' AS language, 'உளுந்து' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'ta' AS language, 'Ulundhu' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'te' AS language, 'మినుములు' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'te' AS language, 'Minumulu' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'kn' AS language, 'ಉದ್ದು' AS term
+    UNION ALL
+    SELECT 'urad' AS slug, 'kn' AS language, 'Uddu' AS term
+    UNION ALL
+    SELECT 'masoor' AS slug, 'en' AS language, 'Red Lentil' AS term
+    UNION ALL
+    SELECT 'masoor' AS slug, 'hi' AS language, 'मसूर' AS term
+    UNION ALL
+    SELECT 'masoor' AS slug, 'ta' AS language, 'மைசூர் பருப்பு' AS term
+    UNION ALL
+    SELECT 'masoor' AS slug, 'ta' AS language, 'Mysore Paruppu' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'hi' AS language, 'हल्दी' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'hi' AS language, 'Haldi' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'ta' AS language, 'மஞ்சள்' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'ta' AS language, 'Manjal' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'te' AS language, 'పసుపు' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'te' AS language, 'Pasupu' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'kn' AS language, 'ಅರಿಶಿನ' AS term
+    UNION ALL
+    SELECT 'turmeric' AS slug, 'kn' AS language, 'Arishina' AS term
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'en' AS language, 'Dry Chilli' AS term
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'hi' AS language, 'लाल मिर्च' AS term
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'hi' AS language, 'Lal Mirch' AS term
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'ta' AS language, 'மிளகாய் வற்றல்' AS term
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'ta' AS language, 'Milagai Vathal' AS term
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'te' AS language, 'ఎండు మిరపకాయ' AS term
+    UNION ALL
+    SELECT 'red-chilli' AS slug, 'te' AS language, 'Endu Mirapakaya' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'hi' AS language, 'इलायची' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'hi' AS language, 'Elaichi' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'ta' AS language, 'ஏலக்காய்' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'ta' AS language, 'Elakkai' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'te' AS language, 'యాలకులు' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'te' AS language, 'Yalakulu' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'kn' AS language, 'ಏಲಕ್ಕಿ' AS term
+    UNION ALL
+    SELECT 'cardamom' AS slug, 'kn' AS language, 'Elakki' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'en' AS language, 'Pepper' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'hi' AS language, 'काली मिर्च' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'hi' AS language, 'Kali Mirch' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'ta' AS language, 'மிளகு' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'ta' AS language, 'Milagu' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'te' AS language, 'మిరియాలు' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'te' AS language, 'Miriyalu' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'kn' AS language, 'ಮೆಣಸು' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'kn' AS language, 'Menasu' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'ml' AS language, 'കുരുമുളക്' AS term
+    UNION ALL
+    SELECT 'black-pepper' AS slug, 'ml' AS language, 'Kurumulaku' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'hi' AS language, 'अदरक' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'hi' AS language, 'Adrak' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'ta' AS language, 'இஞ்சி' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'ta' AS language, 'Inji' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'te' AS language, 'అల్లం' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'te' AS language, 'Allam' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'kn' AS language, 'ಶುಂಠಿ' AS term
+    UNION ALL
+    SELECT 'ginger' AS slug, 'kn' AS language, 'Shunti' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'hi' AS language, 'धनिया' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'hi' AS language, 'Dhaniya' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'ta' AS language, 'கொத்தமல்லி' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'ta' AS language, 'Kothamalli' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'te' AS language, 'ధనియాలు' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'te' AS language, 'Dhaniyalu' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'kn' AS language, 'ಕೊತ್ತಂಬರಿ' AS term
+    UNION ALL
+    SELECT 'coriander' AS slug, 'kn' AS language, 'Kothambari' AS term
+) s
+JOIN crops c ON c.slug = s.slug;
+
+-- Link existing listings whose name matches a crop or one of its synonyms.
+-- A synonym shared by several crops is ambiguous and stays unlinked, as
+-- catalog.Resolve leaves it.
+UPDATE products p JOIN crops c ON c.name = TRIM(p.crop_name)
+SET p.crop_id = c.id;
+
+UPDATE products p JOIN (
+    SELECT term, MIN(crop_id) AS crop_id
+    FROM crop_synonyms
+    GROUP BY term
+    HAVING COUNT(DISTINCT crop_id) = 1
+) s ON s.term = TRIM(p.crop_name)
+SET p.crop_id = s.crop_id
+WHERE p.crop_id IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE products DROP INDEX ft_products_search;
+ALTER TABLE products DROP COLUMN search_keywords, DROP COLUMN description;
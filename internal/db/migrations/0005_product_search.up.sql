@@ -0,0 +1,17 @@
+-- Full-text product search. search_keywords holds the words of a listing that
+-- live in other tables (variety, farm name, catalog name, category and
+-- synonyms) and is kept current by package search.
+
+ALTER TABLE products ADD COLUMN description TEXT NULL AFTER status,
+    ADD COLUMN search_keywords TEXT NULL AFTER description;
+
+-- Backfill with the words search.Document.keywords would store
+UPDATE products p
+LEFT JOIN farmer_profiles fp ON fp.farmer_id = p.farmer_id
+LEFT JOIN crop_varieties v ON v.id = p.variety_id
+LEFT JOIN crops c ON c.id = p.crop_id
+LEFT JOIN crop_categories cc ON cc.id = c.category_id
+SET p.search_keywords = CONCAT_WS(' ', v.name, fp.farm_name, c.name, cc.name,
+    (SELECT GROUP_CONCAT(s.term ORDER BY s.language, s.term SEPARATOR ' ') FROM crop_synonyms s WHERE s.crop_id = p.crop_id));
+
+ALTER TABLE products ADD FULLTEXT INDEX ft_products_search (crop_name, description, search_keywords);
@@ -0,0 +1,4 @@
+ALTER TABLE buyer_profiles DROP COLUMN longitude, DROP COLUMN latitude;
+ALTER TABLE farmer_profiles DROP COLUMN longitude, DROP COLUMN latitude;
+ALTER TABLE products DROP INDEX idx_products_location, DROP COLUMN longitude, DROP COLUMN latitude;
+DROP TABLE pincodes;
@@ -0,0 +1,22 @@
+-- Pincode reference data and coordinates for distance-based discovery.
+-- pincodes starts empty; "server load-pincodes" fills it and sets the
+-- coordinates of existing products and profiles.
+
+CREATE TABLE pincodes (
+    code CHAR(6) PRIMARY KEY,
+    latitude DOUBLE NOT NULL,
+    longitude DOUBLE NOT NULL,
+    district VARCHAR(100),
+    state VARCHAR(100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE products ADD COLUMN latitude DOUBLE NULL AFTER pincode,
+    ADD COLUMN longitude DOUBLE NULL AFTER latitude,
+    ADD INDEX idx_products_location (latitude, longitude);
+
+ALTER TABLE farmer_profiles ADD COLUMN latitude DOUBLE NULL AFTER pincode,
+    ADD COLUMN longitude DOUBLE NULL AFTER latitude;
+
+ALTER TABLE buyer_profiles ADD COLUMN latitude DOUBLE NULL AFTER pincode,
+    ADD COLUMN longitude DOUBLE NULL AFTER latitude;
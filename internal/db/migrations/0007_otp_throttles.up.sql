@@ -0,0 +1,11 @@
+-- Per-phone OTP request counters. Requests are counted here whether or not the
+-- phone is registered, so the rate limit does not reveal which numbers are.
+
+CREATE TABLE otp_throttles (
+    phone VARCHAR(20) NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    window_started_at TIMESTAMP NOT NULL,
+    requests INT NOT NULL DEFAULT 0,
+    last_request_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (phone, purpose)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -29,6 +29,8 @@ func Connect(cfg config.Config) (*gorm.DB, error) {
 
 	gormCfg := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Warn),
+		// Report duplicate keys as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	}
 
 	db, err := gorm.Open(mysql.Open(dsn), gormCfg)
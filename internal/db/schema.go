@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Models lists every persisted model, parents before the tables that reference them
+func Models() []interface{} {
+	return []interface{}{
+		&models.Pincode{},
+		&models.User{},
+		&models.FarmerProfile{},
+		&models.BuyerProfile{},
+		&models.CropCategory{},
+		&models.Crop{},
+		&models.CropVariety{},
+		&models.CropSynonym{},
+		&models.Product{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.StockReservation{},
+		&models.OrderStatusEvent{},
+		&models.Review{},
+		&models.OTPCode{},
+		&models.OTPThrottle{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.AuditLog{},
+	}
+}
+
+// Drift is one difference between a model and its table in the database
+type Drift struct {
+	Table   string
+	Column  string // empty when the whole table is missing
+	Problem string
+}
+
+func (d Drift) String() string {
+	if d.Column == "" {
+		return fmt.Sprintf("%s: %s", d.Table, d.Problem)
+	}
+	return fmt.Sprintf("%s.%s: %s", d.Table, d.Column, d.Problem)
+}
+
+// Verify compares every model with the migrated database schema. It reports
+// missing tables, columns the model uses but the table lacks, columns the
+// model does not know about, and columns whose explicit gorm type differs
+// from the database type. An empty result means the models match the schema.
+func Verify(conn *gorm.DB) ([]Drift, error) {
+	var drifts []Drift
+	for _, model := range Models() {
+		stmt := &gorm.Statement{DB: conn}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		if !conn.Migrator().HasTable(table) {
+			drifts = append(drifts, Drift{Table: table, Problem: "table is missing"})
+			continue
+		}
+
+		columnTypes, err := conn.Migrator().ColumnTypes(table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		dbTypes := make(map[string]string, len(columnTypes))
+		for _, ct := range columnTypes {
+			dbTypes[ct.Name()] = strings.ToLower(ct.DatabaseTypeName())
+		}
+
+		modelColumns := make(map[string]bool)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			modelColumns[field.DBName] = true
+
+			dbType, ok := dbTypes[field.DBName]
+			if !ok {
+				drifts = append(drifts, Drift{Table: table, Column: field.DBName, Problem: "column is missing"})
+				continue
+			}
+			if want := baseType(field.TagSettings["TYPE"]); want != "" && want != dbType {
+				drifts = append(drifts, Drift{
+					Table:   table,
+					Column:  field.DBName,
+					Problem: fmt.Sprintf("model type %s but database type %s", want, dbType),
+				})
+			}
+		}
+
+		var extra []string
+		for name := range dbTypes {
+			if !modelColumns[name] {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			drifts = append(drifts, Drift{Table: table, Column: name, Problem: "column is not mapped by the model"})
+		}
+	}
+	return drifts, nil
+}
+
+// baseType reduces a gorm type tag such as "decimal(10,2)" or "enum('a','b')"
+// to the bare type name MySQL reports in information_schema
+func baseType(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "( "); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
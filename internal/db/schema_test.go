@@ -0,0 +1,33 @@
+package db_test
+
+import (
+	"testing"
+
+	"farmer-to-buyer-portal/internal/db"
+	"farmer-to-buyer-portal/internal/db/migrations"
+	"farmer-to-buyer-portal/internal/dbtest"
+)
+
+// TestMigrationsMatchModels fails when a model changes without a migration
+func TestMigrationsMatchModels(t *testing.T) {
+	conn := dbtest.Open(t)
+
+	if _, err := migrations.Up(conn); err != nil {
+		t.Fatalf("migrations.Up() error = %v", err)
+	}
+	pending, err := migrations.Pending(conn)
+	if err != nil {
+		t.Fatalf("migrations.Pending() error = %v", err)
+	}
+	if len(pending) > 0 {
+		t.Fatalf("%d migration(s) still pending after Up", len(pending))
+	}
+
+	drifts, err := db.Verify(conn)
+	if err != nil {
+		t.Fatalf("db.Verify() error = %v", err)
+	}
+	for _, d := range drifts {
+		t.Errorf("schema drift: %s", d)
+	}
+}
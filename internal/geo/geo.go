@@ -0,0 +1,93 @@
+// Package geo locates Indian PIN codes and measures distances between them.
+//
+// Locations come from a reference table of PIN code centres loaded from CSV
+// (see LoadCSV). Products and profiles copy the coordinates of their pincode
+// when saved, so distance queries never join the reference table.
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EarthRadiusKm is the mean radius of the Earth
+const EarthRadiusKm = 6371.0
+
+// MaxRadiusKm bounds search radii; it spans India end to end
+const MaxRadiusKm = 4000.0
+
+// Point is a position in decimal degrees
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// LatLng returns the coordinates to store on a model, both nil for a nil point
+func (p *Point) LatLng() (*float64, *float64) {
+	if p == nil {
+		return nil, nil
+	}
+	lat, lng := p.Lat, p.Lng
+	return &lat, &lng
+}
+
+// Locate returns the centre of pincode, or nil when the reference data does
+// not know it
+func Locate(db *gorm.DB, pincode string) (*Point, error) {
+	var row models.Pincode
+	if err := db.Where("code = ?", pincode).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Point{Lat: row.Latitude, Lng: row.Longitude}, nil
+}
+
+// Distance returns the great-circle distance between a and b in kilometres
+func Distance(a, b Point) float64 {
+	dLat := radians(b.Lat - a.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(a.Lat))*math.Cos(radians(b.Lat))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// DistanceSQL returns a SQL expression for the distance in kilometres from
+// origin to the point in latColumn and lngColumn, computed as Distance does and
+// rounded to the metre so that equal distances compare equal, with the values
+// for its placeholders
+func DistanceSQL(latColumn, lngColumn string, origin Point) (string, []interface{}) {
+	expr := fmt.Sprintf("ROUND(2 * %[1]g * ASIN(LEAST(1, SQRT("+
+		"POWER(SIN(RADIANS(%[2]s - ?) / 2), 2) + "+
+		"COS(RADIANS(?)) * COS(RADIANS(%[2]s)) * POWER(SIN(RADIANS(%[3]s - ?) / 2), 2)))), 3)",
+		EarthRadiusKm, latColumn, lngColumn)
+	return expr, []interface{}{origin.Lat, origin.Lat, origin.Lng}
+}
+
+// BoundingBox returns latitude and longitude ranges that contain every point
+// within radiusKm of center. It lets an index narrow a distance query before
+// the exact distance is computed. Ranges are not wrapped at the poles or the
+// antimeridian, neither of which is near India.
+func BoundingBox(center Point, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	dLat := degrees(radiusKm / EarthRadiusKm)
+	minLat, maxLat = math.Max(center.Lat-dLat, -90), math.Min(center.Lat+dLat, 90)
+
+	// Meridians converge away from the equator, so a degree of longitude is
+	// shortest at the latitude furthest from it
+	widest := math.Max(math.Abs(minLat), math.Abs(maxLat))
+	if widest >= 89 {
+		return minLat, maxLat, -180, 180
+	}
+	dLng := degrees(radiusKm / (EarthRadiusKm * math.Cos(radians(widest))))
+	return minLat, maxLat, center.Lng - dLng, center.Lng + dLng
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
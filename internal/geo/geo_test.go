@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var (
+	delhi     = Point{Lat: 28.6139, Lng: 77.2090}
+	mumbai    = Point{Lat: 19.0760, Lng: 72.8777}
+	bengaluru = Point{Lat: 12.9716, Lng: 77.5946}
+	chennai   = Point{Lat: 13.0827, Lng: 80.2707}
+	kolkata   = Point{Lat: 22.5726, Lng: 88.3639}
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64 // published great-circle distance in km
+	}{
+		{"Delhi to Mumbai", delhi, mumbai, 1148},
+		{"Bengaluru to Chennai", bengaluru, chennai, 290},
+		{"Kolkata to Delhi", kolkata, delhi, 1304},
+		{"Delhi to itself", delhi, delhi, 0},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+			t.Errorf("%s: Distance() = %.1f km, want %.0f km within 1 km", tt.name, got, tt.want)
+		}
+		if got, back := Distance(tt.a, tt.b), Distance(tt.b, tt.a); math.Abs(got-back) > 1e-9 {
+			t.Errorf("%s: Distance() = %v one way and %v the other", tt.name, got, back)
+		}
+	}
+}
+
+// destination returns the point distanceKm from origin along bearing (degrees
+// clockwise from north)
+func destination(origin Point, bearing, distanceKm float64) Point {
+	lat, lng, theta := radians(origin.Lat), radians(origin.Lng), radians(bearing)
+	delta := distanceKm / EarthRadiusKm
+	lat2 := math.Asin(math.Sin(lat)*math.Cos(delta) + math.Cos(lat)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat), math.Cos(delta)-math.Sin(lat)*math.Sin(lat2))
+	return Point{Lat: degrees(lat2), Lng: degrees(lng2)}
+}
+
+func TestBoundingBoxContainsRadius(t *testing.T) {
+	tests := []struct {
+		center   Point
+		radiusKm float64
+	}{
+		{delhi, 1},
+		{delhi, 50},
+		{bengaluru, 250},
+		{kolkata, 1000},
+		{Point{Lat: 8.0883, Lng: 77.5385}, 500},  // Kanyakumari
+		{Point{Lat: 34.1526, Lng: 77.5771}, 500}, // Leh
+	}
+	for _, tt := range tests {
+		minLat, maxLat, minLng, maxLng := BoundingBox(tt.center, tt.radiusKm)
+		for bearing := 0.0; bearing < 360; bearing += 5 {
+			p := destination(tt.center, bearing, tt.radiusKm)
+			if d := Distance(tt.center, p); math.Abs(d-tt.radiusKm) > 1e-6*math.Max(1, tt.radiusKm) {
+				t.Fatalf("destination() is %v km away, want %v", d, tt.radiusKm)
+			}
+			const eps = 1e-9
+			if p.Lat < minLat-eps || p.Lat > maxLat+eps || p.Lng < minLng-eps || p.Lng > maxLng+eps {
+				t.Errorf("BoundingBox(%v, %v km) = [%v, %v] x [%v, %v] misses %v at bearing %v",
+					tt.center, tt.radiusKm, minLat, maxLat, minLng, maxLng, p, bearing)
+			}
+		}
+	}
+}
+
+func TestBoundingBoxNearPole(t *testing.T) {
+	_, maxLat, minLng, maxLng := BoundingBox(Point{Lat: 88.5, Lng: 10}, 200)
+	if maxLat != 90 || minLng != -180 || maxLng != 180 {
+		t.Errorf("BoundingBox near the pole = lat up to %v, lng [%v, %v], want 90 and every longitude", maxLat, minLng, maxLng)
+	}
+}
+
+func TestDistanceSQL(t *testing.T) {
+	expr, args := DistanceSQL("products.latitude", "products.longitude", delhi)
+	if n := strings.Count(expr, "?"); n != len(args) {
+		t.Fatalf("DistanceSQL() has %d placeholders for %d args", n, len(args))
+	}
+	want := []interface{}{delhi.Lat, delhi.Lat, delhi.Lng}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("DistanceSQL() args = %v, want %v", args, want)
+			break
+		}
+	}
+	for _, part := range []string{"RADIANS(products.latitude - ?)", "RADIANS(products.longitude - ?)", "2 * 6371 *", ", 3)"} {
+		if !strings.Contains(expr, part) {
+			t.Errorf("DistanceSQL() = %s, want it to contain %q", expr, part)
+		}
+	}
+}
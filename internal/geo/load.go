@@ -0,0 +1,178 @@
+package geo
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// saveBatchSize bounds how many pincodes one INSERT writes
+const saveBatchSize = 1000
+
+// LoadSummary counts the work done by LoadCSV
+type LoadSummary struct {
+	Rows     int // data rows read
+	Pincodes int // distinct pincodes stored
+	Skipped  int // rows with a malformed pincode or coordinates outside India
+}
+
+// columnNames lists the header names accepted for each column, compared after
+// lower-casing and dropping spaces and underscores
+var columnNames = map[string][]string{
+	"pincode":   {"pincode", "pin", "postalcode"},
+	"latitude":  {"latitude", "lat"},
+	"longitude": {"longitude", "lng", "lon", "long"},
+	"district":  {"district", "districtname"},
+	"state":     {"state", "statename"},
+}
+
+// indiaBounds rejects the zero, swapped and out-of-country coordinates common
+// in public PIN code data
+var indiaBounds = struct{ minLat, maxLat, minLng, maxLng float64 }{6, 38, 68, 98}
+
+// LoadCSV reads PIN code centres from r and stores them, replacing what was
+// known for the same codes, then copies the coordinates onto the products and
+// profiles at those pincodes.
+//
+// The first row is a header naming the columns pincode, latitude and
+// longitude, and optionally district and state, in any order; the India Post
+// directory's names such as statename are accepted too. A pincode listed on
+// several rows, one per post office, is placed at their average.
+func LoadCSV(db *gorm.DB, r io.Reader) (LoadSummary, error) {
+	var summary LoadSummary
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return summary, errors.New("pincode file is empty")
+		}
+		return summary, err
+	}
+	columns, err := headerColumns(header)
+	if err != nil {
+		return summary, err
+	}
+
+	type centre struct {
+		lat, lng        float64
+		n               int
+		district, state string
+	}
+	centres := make(map[string]*centre)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return summary, err
+		}
+		summary.Rows++
+
+		code := field(record, columns, "pincode")
+		lat, latErr := strconv.ParseFloat(field(record, columns, "latitude"), 64)
+		lng, lngErr := strconv.ParseFloat(field(record, columns, "longitude"), 64)
+		if utils.ValidatePincode(code) != nil || latErr != nil || lngErr != nil ||
+			lat < indiaBounds.minLat || lat > indiaBounds.maxLat ||
+			lng < indiaBounds.minLng || lng > indiaBounds.maxLng {
+			summary.Skipped++
+			continue
+		}
+
+		c, ok := centres[code]
+		if !ok {
+			c = &centre{district: field(record, columns, "district"), state: field(record, columns, "state")}
+			centres[code] = c
+		}
+		c.lat += lat
+		c.lng += lng
+		c.n++
+	}
+
+	pincodes := make([]models.Pincode, 0, len(centres))
+	for code, c := range centres {
+		pincodes = append(pincodes, models.Pincode{
+			Code:      code,
+			Latitude:  c.lat / float64(c.n),
+			Longitude: c.lng / float64(c.n),
+			District:  c.district,
+			State:     c.state,
+		})
+	}
+	sort.Slice(pincodes, func(i, j int) bool { return pincodes[i].Code < pincodes[j].Code })
+	summary.Pincodes = len(pincodes)
+	if len(pincodes) == 0 {
+		return summary, nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoUpdates: clause.AssignmentColumns([]string{"latitude", "longitude", "district", "state", "updated_at"}),
+		}).CreateInBatches(&pincodes, saveBatchSize).Error; err != nil {
+			return err
+		}
+		return locateAll(tx)
+	})
+	return summary, err
+}
+
+// locateAll copies pincode coordinates onto every product and profile,
+// leaving updated_at alone since the owner changed nothing
+func locateAll(tx *gorm.DB) error {
+	for _, table := range []string{"products", "farmer_profiles", "buyer_profiles"} {
+		if err := tx.Exec("UPDATE " + table + " t JOIN pincodes z ON z.code = t.pincode " +
+			"SET t.latitude = z.latitude, t.longitude = z.longitude, t.updated_at = t.updated_at").Error; err != nil {
+			return fmt.Errorf("failed to locate %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// headerColumns maps each known column to its index in header
+func headerColumns(header []string) (map[string]int, error) {
+	normalize := strings.NewReplacer(" ", "", "_", "", "\ufeff", "")
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = normalize.Replace(strings.ToLower(strings.TrimSpace(name)))
+		for column, aliases := range columnNames {
+			for _, alias := range aliases {
+				if name == alias {
+					if _, dup := columns[column]; !dup {
+						columns[column] = i
+					}
+				}
+			}
+		}
+	}
+	for _, required := range []string{"pincode", "latitude", "longitude"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("pincode file has no %s column", required)
+		}
+	}
+	return columns, nil
+}
+
+// field returns the trimmed value of column in record, or "" if the record is short
+func field(record []string, columns map[string]int, column string) string {
+	i, ok := columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"farmer-to-buyer-portal/internal/audit"
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/otp"
+	"farmer-to-buyer-portal/internal/pagination"
+	"farmer-to-buyer-portal/internal/tokens"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// adminListSorts are the orderings the admin user and audit log listings
+// accept; the first is the default
+var adminListSorts = []pagination.Sort{
+	{Name: "newest", Column: "created_at", Kind: pagination.KindTime, Desc: true},
+	{Name: "oldest", Column: "created_at", Kind: pagination.KindTime},
+}
+
+// errSelfModeration is returned when an admin tries to moderate their own account
+var errSelfModeration = errors.New("admins cannot moderate their own account")
+
+// AdminUserResponse represents a user as seen by admins
+type AdminUserResponse struct {
+	ID                string `json:"id"`
+	Phone             string `json:"phone"`
+	Name              string `json:"name"`
+	Role              string `json:"role"`
+	IsVerified        bool   `json:"is_verified"`
+	IsActive          bool   `json:"is_active"`
+	MustResetPassword bool   `json:"must_reset_password"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+// UpdateUserStatusRequest represents the request payload for toggling account flags
+type UpdateUserStatusRequest struct {
+	IsActive   *bool  `json:"is_active"`
+	IsVerified *bool  `json:"is_verified"`
+	Reason     string `json:"reason" binding:"max=500"`
+}
+
+// ForcePasswordResetRequest represents the request payload for forcing a password reset
+type ForcePasswordResetRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// AuditLogResponse represents an audit log entry in API responses
+type AuditLogResponse struct {
+	ID         string          `json:"id"`
+	ActorID    string          `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Reason     string          `json:"reason,omitempty"`
+	Details    json.RawMessage `json:"details,omitempty"`
+	IPAddress  string          `json:"ip_address"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+// toAdminUserResponse converts a User model to AdminUserResponse
+func toAdminUserResponse(user models.User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:                user.ID,
+		Phone:             user.Phone,
+		Name:              user.Name,
+		Role:              user.Role,
+		IsVerified:        user.IsVerified,
+		IsActive:          user.IsActive,
+		MustResetPassword: user.MustResetPassword,
+		CreatedAt:         user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:         user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// toAuditLogResponse converts an AuditLog model to AuditLogResponse
+func toAuditLogResponse(a models.AuditLog) AuditLogResponse {
+	response := AuditLogResponse{
+		ID:         a.ID,
+		ActorID:    a.ActorID,
+		Action:     a.Action,
+		TargetType: a.TargetType,
+		TargetID:   a.TargetID,
+		Reason:     a.Reason,
+		IPAddress:  a.IPAddress,
+		CreatedAt:  a.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if a.Details != "" {
+		response.Details = json.RawMessage(a.Details)
+	}
+	return response
+}
+
+// adminListing is a parsed admin list request
+type adminListing struct {
+	req   pagination.Request
+	total int64
+}
+
+// startAdminListing reads the paging parameters of an admin listing and
+// prepares query to fetch the requested page. Admin listings always answer
+// with a page; the bare array kept for older product and order clients does
+// not apply to them. It writes the error response and returns false on failure.
+func startAdminListing(c *gin.Context, query *gorm.DB, sorts []pagination.Sort, fallback string) (*gorm.DB, adminListing, bool) {
+	var listing adminListing
+	var err error
+	listing.req, err = pagination.Parse(c.Query("limit"), c.Query("cursor"), c.Query("sort"), sorts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, listing, false
+	}
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&listing.total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+		return nil, listing, false
+	}
+	return listing.req.Apply(query), listing, true
+}
+
+// page returns the page metadata and how many of the fetched rows to return
+func (l adminListing) page(fetched int, keyAt func(i int) (interface{}, string)) (pagination.Page, int) {
+	return l.req.Page(fetched, l.total, keyAt)
+}
+
+// adminAuditEntry starts an audit entry for an action by the current admin on a user
+func adminAuditEntry(c *gin.Context, action, userID, reason string) audit.Entry {
+	return audit.Entry{
+		ActorID:    c.MustGet("user_id").(string),
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Reason:     reason,
+		IPAddress:  c.ClientIP(),
+	}
+}
+
+// findAdminTargetUser loads the user named by the :id path parameter and records
+// that the admin viewed it. It writes the error response and returns false on failure.
+func findAdminTargetUser(c *gin.Context, action string) (models.User, bool) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	var user models.User
+	if err := db.Where("id = ?", c.Param("id")).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return user, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return user, false
+	}
+
+	if err := audit.Record(db, adminAuditEntry(c, action, user.ID, "")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write audit log"})
+		return user, false
+	}
+	return user, true
+}
+
+// AdminUserListResponse is one page of users
+type AdminUserListResponse struct {
+	Items []AdminUserResponse `json:"items"`
+	Page  pagination.Page     `json:"page"`
+}
+
+// AdminListUsers handles GET /api/v1/admin/users.
+// Supports q (name or phone), role, is_active and is_verified filters and
+// pages with limit, cursor and sort (newest or oldest).
+func AdminListUsers(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	query := db.Model(&models.User{})
+
+	if q := c.Query("q"); q != "" {
+		query = query.Where("name LIKE ? OR phone LIKE ?", "%"+q+"%", "%"+q+"%")
+	}
+	if role := c.Query("role"); role != "" {
+		if !authz.IsRole(role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "role must be one of farmer, buyer, admin"})
+			return
+		}
+		query = query.Where("role = ?", role)
+	}
+	for _, flag := range []string{"is_active", "is_verified"} {
+		raw := c.Query(flag)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": flag + " must be true or false"})
+			return
+		}
+		query = query.Where(flag+" = ?", value)
+	}
+
+	query, listing, ok := startAdminListing(c, query, adminListSorts, "Failed to fetch users")
+	if !ok {
+		return
+	}
+
+	var users []models.User
+	if err := query.Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	page, n := listing.page(len(users), func(i int) (interface{}, string) {
+		return users[i].CreatedAt, users[i].ID
+	})
+	responses := make([]AdminUserResponse, n)
+	for i, user := range users[:n] {
+		responses[i] = toAdminUserResponse(user)
+	}
+
+	c.JSON(http.StatusOK, AdminUserListResponse{Items: responses, Page: page})
+}
+
+// AdminGetUser handles GET /api/v1/admin/users/:id
+func AdminGetUser(c *gin.Context) {
+	user, ok := findAdminTargetUser(c, audit.ActionUserView)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// AdminUpdateUserStatus handles PUT /api/v1/admin/users/:id/status.
+// Deactivating a user also revokes all of their sessions.
+func AdminUpdateUserStatus(c *gin.Context) {
+	var req UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.IsActive == nil && req.IsVerified == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide is_active and/or is_verified"})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	adminID := c.MustGet("user_id").(string)
+	userID := c.Param("id")
+
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+		if user.ID == adminID {
+			return errSelfModeration
+		}
+
+		updates := map[string]interface{}{}
+		var entries []audit.Entry
+
+		if req.IsActive != nil && *req.IsActive != user.IsActive {
+			updates["is_active"] = *req.IsActive
+			action := audit.ActionUserActivate
+			if !*req.IsActive {
+				action = audit.ActionUserDeactivate
+			}
+			entries = append(entries, adminAuditEntry(c, action, user.ID, req.Reason))
+		}
+		if req.IsVerified != nil && *req.IsVerified != user.IsVerified {
+			updates["is_verified"] = *req.IsVerified
+			action := audit.ActionUserVerify
+			if !*req.IsVerified {
+				action = audit.ActionUserUnverify
+			}
+			entries = append(entries, adminAuditEntry(c, action, user.ID, req.Reason))
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		if req.IsActive != nil && !*req.IsActive {
+			if err := tokens.RevokeAllSessions(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		for _, entry := range entries {
+			if err := audit.Record(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondAdminUserError(c, err, "Failed to update user")
+		return
+	}
+
+	c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// AdminForcePasswordReset handles POST /api/v1/admin/users/:id/force-password-reset.
+// The user is logged out everywhere and cannot log in until they reset their
+// password through the OTP flow; a reset code is sent to their phone.
+func AdminForcePasswordReset(c *gin.Context) {
+	var req ForcePasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	adminID := c.MustGet("user_id").(string)
+	userID := c.Param("id")
+
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+		if user.ID == adminID {
+			return errSelfModeration
+		}
+
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"must_reset_password": true,
+			"token_version":       gorm.Expr("token_version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := tokens.RevokeAllSessions(tx, user.ID); err != nil {
+			return err
+		}
+		return audit.Record(tx, adminAuditEntry(c, audit.ActionUserForcePasswordReset, user.ID, req.Reason))
+	})
+	if err != nil {
+		respondAdminUserError(c, err, "Failed to force password reset")
+		return
+	}
+
+	// The account is locked either way; the user can request another code if this one fails
+	if user.IsActive {
+		if err := otp.Issue(db, user.Phone, otp.PurposePasswordReset); err != nil {
+			log.Printf("WARNING: failed to send forced reset code to user %s: %v", user.ID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset required. The user has been logged out of all sessions."})
+}
+
+// AdminGetUserProducts handles GET /api/v1/admin/users/:id/products.
+// Pages like the product listing, but always answers with a page.
+func AdminGetUserProducts(c *gin.Context) {
+	user, ok := findAdminTargetUser(c, audit.ActionUserViewProducts)
+	if !ok {
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	writeProductPage(c, db.Model(&models.Product{}).Where("farmer_id = ?", user.ID), toOwnerProductResponse, nil, true)
+}
+
+// AdminGetUserOrders handles GET /api/v1/admin/users/:id/orders.
+// Returns orders where the user is either the buyer or the farmer, paged like
+// the order listings but always as a page.
+func AdminGetUserOrders(c *gin.Context) {
+	user, ok := findAdminTargetUser(c, audit.ActionUserViewOrders)
+	if !ok {
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	writeOrderPage(c, db.Model(&models.Order{}).Where("buyer_id = ? OR farmer_id = ?", user.ID, user.ID), true)
+}
+
+// AuditLogListResponse is one page of audit log entries
+type AuditLogListResponse struct {
+	Items []AuditLogResponse `json:"items"`
+	Page  pagination.Page    `json:"page"`
+}
+
+// AdminGetAuditLogs handles GET /api/v1/admin/audit-logs.
+// Supports actor_id, target_id and action filters and pages with limit,
+// cursor and sort (newest or oldest).
+func AdminGetAuditLogs(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	query := db.Model(&models.AuditLog{})
+	if actorID := c.Query("actor_id"); actorID != "" {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	query, listing, ok := startAdminListing(c, query, adminListSorts, "Failed to fetch audit logs")
+	if !ok {
+		return
+	}
+
+	var logs []models.AuditLog
+	if err := query.Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs"})
+		return
+	}
+
+	page, n := listing.page(len(logs), func(i int) (interface{}, string) {
+		return logs[i].CreatedAt, logs[i].ID
+	})
+	responses := make([]AuditLogResponse, n)
+	for i, entry := range logs[:n] {
+		responses[i] = toAuditLogResponse(entry)
+	}
+
+	c.JSON(http.StatusOK, AuditLogListResponse{Items: responses, Page: page})
+}
+
+// respondAdminUserError maps a user moderation failure to an HTTP response
+func respondAdminUserError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, errSelfModeration):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot moderate your own account"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
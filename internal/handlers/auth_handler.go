@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"errors"
+	"log"
 	"net/http"
 
 	"farmer-to-buyer-portal/internal/models"
-	"farmer-to-buyer-portal/internal/utils"
+	"farmer-to-buyer-portal/internal/otp"
+	"farmer-to-buyer-portal/internal/tokens"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -18,6 +20,10 @@ type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Password string `json:"password" binding:"required,min=6"`
 	Role     string `json:"role" binding:"required,oneof=farmer buyer"`
+
+	// Optional profile created together with the account; must match Role
+	FarmerProfile *FarmerProfileRequest `json:"farmer_profile"`
+	BuyerProfile  *BuyerProfileRequest  `json:"buyer_profile"`
 }
 
 // LoginRequest represents the login request payload
@@ -28,16 +34,73 @@ type LoginRequest struct {
 
 // UserResponse represents the user data in API responses
 type UserResponse struct {
-	ID    string `json:"id"`
-	Phone string `json:"phone"`
-	Name  string `json:"name"`
-	Role  string `json:"role"`
+	ID         string `json:"id"`
+	Phone      string `json:"phone"`
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	IsVerified bool   `json:"is_verified"`
+}
+
+// MeResponse represents the current user together with their profile, if any
+type MeResponse struct {
+	UserResponse
+	FarmerProfile *FarmerProfileResponse `json:"farmer_profile,omitempty"`
+	BuyerProfile  *BuyerProfileResponse  `json:"buyer_profile,omitempty"`
+}
+
+// RefreshRequest represents the request payload for refreshing an access token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 // AuthResponse represents the authentication response
 type AuthResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int          `json:"expires_in"` // access token lifetime in seconds
+	User         UserResponse `json:"user"`
+}
+
+// toUserResponse converts a User model to UserResponse
+func toUserResponse(user models.User) UserResponse {
+	return UserResponse{
+		ID:         user.ID,
+		Phone:      user.Phone,
+		Name:       user.Name,
+		Role:       user.Role,
+		IsVerified: user.IsVerified,
+	}
+}
+
+// toAuthResponse converts a token pair and its user to AuthResponse
+func toAuthResponse(pair tokens.Pair, user models.User) AuthResponse {
+	return AuthResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    int(pair.ExpiresIn.Seconds()),
+		User:         toUserResponse(user),
+	}
+}
+
+// clientInfo describes the device making the request, for session records
+func clientInfo(c *gin.Context) tokens.Client {
+	return tokens.Client{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// respondWithToken starts a new session for user and writes the authentication response
+func respondWithToken(c *gin.Context, user models.User) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	pair, err := tokens.Issue(db, user, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuthResponse(pair, user))
 }
 
 // Register handles user registration
@@ -48,6 +111,28 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	// Validate the optional profile against the requested role
+	if req.FarmerProfile != nil {
+		if req.Role != "farmer" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "farmer_profile can only be provided when registering as a farmer"})
+			return
+		}
+		if err := req.FarmerProfile.validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.BuyerProfile != nil {
+		if req.Role != "buyer" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "buyer_profile can only be provided when registering as a buyer"})
+			return
+		}
+		if err := req.BuyerProfile.validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	db := c.MustGet("db").(*gorm.DB)
 
 	// Check if phone already exists
@@ -74,27 +159,38 @@ func Register(c *gin.Context) {
 		IsActive:     true,
 	}
 
-	if err := db.Create(&user).Error; err != nil {
+	// Create the user and optional profile together
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if req.FarmerProfile != nil {
+			profile := models.FarmerProfile{FarmerID: user.ID}
+			req.FarmerProfile.apply(&profile)
+			if err := tx.Create(&profile).Error; err != nil {
+				return err
+			}
+		}
+		if req.BuyerProfile != nil {
+			profile := models.BuyerProfile{BuyerID: user.ID}
+			req.BuyerProfile.apply(&profile)
+			if err := tx.Create(&profile).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Role)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
-		return
+	// Send the phone verification code; the account works without it until verified
+	if err := otp.Issue(db, user.Phone, otp.PurposeVerifyPhone); err != nil {
+		log.Printf("WARNING: failed to send verification code to new user %s: %v", user.ID, err)
 	}
 
-	c.JSON(http.StatusOK, AuthResponse{
-		Token: token,
-		User: UserResponse{
-			ID:    user.ID,
-			Phone: user.Phone,
-			Name:  user.Name,
-			Role:  user.Role,
-		},
-	})
+	respondWithToken(c, user)
 }
 
 // Login handles user login
@@ -130,22 +226,52 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Role)
+	if user.MustResetPassword {
+		respondPasswordResetRequired(c)
+		return
+	}
+
+	respondWithToken(c, user)
+}
+
+// Refresh handles POST /api/v1/auth/refresh, rotating the refresh token
+func Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	pair, user, err := tokens.Rotate(db, req.RefreshToken, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		switch {
+		case errors.Is(err, tokens.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has already been used; the session has been revoked, please log in again"})
+		case errors.Is(err, tokens.ErrInvalidRefreshToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, AuthResponse{
-		Token: token,
-		User: UserResponse{
-			ID:    user.ID,
-			Phone: user.Phone,
-			Name:  user.Name,
-			Role:  user.Role,
-		},
-	})
+	c.JSON(http.StatusOK, toAuthResponse(pair, user))
+}
+
+// Logout handles POST /api/v1/auth/logout, revoking the current session
+func Logout(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+	sessionID := c.MustGet("session_id").(string)
+
+	if err := tokens.RevokeSession(db, userID, sessionID); err != nil && !errors.Is(err, tokens.ErrSessionNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
 // Me returns the current authenticated user's details
@@ -163,10 +289,29 @@ func Me(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:    user.ID,
-		Phone: user.Phone,
-		Name:  user.Name,
-		Role:  user.Role,
-	})
+	response := MeResponse{UserResponse: toUserResponse(user)}
+
+	// Attach the role's profile when one exists
+	switch user.Role {
+	case "farmer":
+		var profile models.FarmerProfile
+		if err := db.Where("farmer_id = ?", user.ID).First(&profile).Error; err == nil {
+			profileResponse := toFarmerProfileResponse(profile)
+			response.FarmerProfile = &profileResponse
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+	case "buyer":
+		var profile models.BuyerProfile
+		if err := db.Where("buyer_id = ?", user.ID).First(&profile).Error; err == nil {
+			profileResponse := toBuyerProfileResponse(profile)
+			response.BuyerProfile = &profileResponse
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
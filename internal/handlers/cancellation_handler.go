@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/orderstate"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CancelOrderRequest represents the request payload for a buyer cancelling an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// ResolveCancellationRequest represents the farmer's decision on a cancellation request
+type ResolveCancellationRequest struct {
+	Approve *bool `json:"approve" binding:"required"`
+}
+
+// errCancellationConflict carries a user-facing reason a cancellation cannot proceed
+type errCancellationConflict struct {
+	message string
+	status  string
+}
+
+func (e *errCancellationConflict) Error() string {
+	return e.message
+}
+
+// CancelOrder handles POST /api/v1/orders/:id/cancel (buyer only).
+// Pending orders are cancelled immediately; accepted orders get a cancellation
+// request that the farmer must approve or decline.
+func CancelOrder(c *gin.Context) {
+	// The reason is optional, so an empty body is allowed
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	orderID := c.Param("id")
+	buyerID := c.MustGet("user_id").(string)
+	actor := orderstate.Actor{ID: buyerID, Role: orderstate.RoleBuyer}
+
+	err := orderstate.Run(db, func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND buyer_id = ?", orderID, buyerID).
+			First(&order).Error; err != nil {
+			return err
+		}
+
+		switch orderstate.State(order.Status) {
+		case orderstate.Pending:
+			// Nothing has been committed yet, so cancel outright
+			return orderstate.Default.Transition(tx, &order, orderstate.Cancelled, actor, req.Reason)
+		case orderstate.Accepted:
+			if order.CancellationRequested {
+				return &errCancellationConflict{message: "Cancellation has already been requested for this order", status: order.Status}
+			}
+			if err := tx.Model(&order).Updates(map[string]interface{}{
+				"cancellation_requested": true,
+				"cancellation_reason":    req.Reason,
+			}).Error; err != nil {
+				return err
+			}
+			note := cancellationNote("Cancellation requested", req.Reason)
+			if err := orderstate.RecordEvent(tx, order.ID, actor, orderstate.Accepted, orderstate.Accepted, note); err != nil {
+				return err
+			}
+			return orderstate.NotifyCounterparty(tx, &order, actor, "Order cancellation requested",
+				fmt.Sprintf("The buyer asked to cancel order %s. %s", order.ID, note))
+		default:
+			return &errCancellationConflict{message: "Order can no longer be cancelled", status: order.Status}
+		}
+	})
+	if err != nil {
+		respondCancellationError(c, err, "Failed to cancel order")
+		return
+	}
+
+	respondWithOrder(c, orderID)
+}
+
+// ResolveCancellation handles PUT /api/v1/orders/:id/cancellation (farmer only).
+// Approving cancels the order and returns its stock to the listings.
+func ResolveCancellation(c *gin.Context) {
+	var req ResolveCancellationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	orderID := c.Param("id")
+	farmerID := c.MustGet("user_id").(string)
+	actor := orderstate.Actor{ID: farmerID, Role: orderstate.RoleFarmer}
+
+	err := orderstate.Run(db, func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND farmer_id = ?", orderID, farmerID).
+			First(&order).Error; err != nil {
+			return err
+		}
+
+		if *req.Approve {
+			return orderstate.Default.Transition(tx, &order, orderstate.Cancelled, actor, "Cancellation request approved")
+		}
+
+		if orderstate.State(order.Status) != orderstate.Accepted {
+			return errNothingToResolve(orderstate.State(order.Status))
+		}
+		if !order.CancellationRequested {
+			return orderstate.ErrNoCancellationRequest
+		}
+		if err := tx.Model(&order).Update("cancellation_requested", false).Error; err != nil {
+			return err
+		}
+		if err := orderstate.RecordEvent(tx, order.ID, actor, orderstate.Accepted, orderstate.Accepted, "Cancellation request declined"); err != nil {
+			return err
+		}
+		return orderstate.NotifyCounterparty(tx, &order, actor, "Order cancellation declined",
+			fmt.Sprintf("The farmer declined your request to cancel order %s", order.ID))
+	})
+	if err != nil {
+		respondCancellationError(c, err, "Failed to resolve cancellation request")
+		return
+	}
+
+	respondWithOrder(c, orderID)
+}
+
+// cancellationNote prefixes an optional buyer reason with a fixed label
+func cancellationNote(label, reason string) string {
+	if reason == "" {
+		return label
+	}
+	return label + ": " + reason
+}
+
+// errNothingToResolve reports that an order in status has no cancellation
+// request a farmer could approve or decline
+func errNothingToResolve(status orderstate.State) *errCancellationConflict {
+	return &errCancellationConflict{
+		message: fmt.Sprintf("Order is %s; only accepted orders can have a cancellation request", status),
+		status:  string(status),
+	}
+}
+
+// respondCancellationError maps a cancellation failure to an HTTP response
+func respondCancellationError(c *gin.Context, err error, fallback string) {
+	var conflict *errCancellationConflict
+	var transitionErr *orderstate.TransitionError
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.message, "current_status": conflict.status})
+	case errors.As(err, &transitionErr):
+		// Approving moves the order to cancelled, which only accepted orders allow
+		conflict = errNothingToResolve(transitionErr.From)
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.message, "current_status": conflict.status})
+	default:
+		respondTransitionError(c, err, fallback)
+	}
+}
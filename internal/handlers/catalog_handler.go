@@ -0,0 +1,697 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"farmer-to-buyer-portal/internal/audit"
+	"farmer-to-buyer-portal/internal/catalog"
+	"farmer-to-buyer-portal/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errInvalidCropName is returned when a crop name has nothing to build a slug from
+var errInvalidCropName = errors.New("crop name must contain latin letters or digits")
+
+// CropCategoryRequest represents the request payload for creating or renaming a category
+type CropCategoryRequest struct {
+	Slug string `json:"slug" binding:"omitempty,max=50"` // derived from name when empty; fixed once created
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// CropSynonymRequest represents the request payload for adding a synonym to a crop
+type CropSynonymRequest struct {
+	Language string `json:"language" binding:"required"`
+	Term     string `json:"term" binding:"required,max=100"`
+}
+
+// CropVarietyRequest represents the request payload for adding a variety to a crop
+type CropVarietyRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// CreateCropRequest represents the request payload for adding a crop to the catalog
+type CreateCropRequest struct {
+	Name      string               `json:"name" binding:"required,max=100"`
+	Category  string               `json:"category" binding:"required"` // category slug
+	Varieties []string             `json:"varieties" binding:"dive,required,max=100"`
+	Synonyms  []CropSynonymRequest `json:"synonyms" binding:"dive"`
+}
+
+// UpdateCropRequest represents the request payload for renaming or recategorising a crop
+type UpdateCropRequest struct {
+	Name     *string `json:"name" binding:"omitempty,max=100"`
+	Category *string `json:"category"`
+}
+
+// CropCategoryResponse represents a crop category in API responses
+type CropCategoryResponse struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// CropVarietyResponse represents a crop variety in API responses
+type CropVarietyResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CropSynonymResponse represents a crop synonym in API responses
+type CropSynonymResponse struct {
+	ID       string `json:"id"`
+	Language string `json:"language"`
+	Term     string `json:"term"`
+}
+
+// CropResponse represents a catalog crop in API responses
+type CropResponse struct {
+	ID        string                `json:"id"`
+	Slug      string                `json:"slug"`
+	Name      string                `json:"name"`
+	Category  CropCategoryResponse  `json:"category"`
+	Varieties []CropVarietyResponse `json:"varieties"`
+	Synonyms  []CropSynonymResponse `json:"synonyms"`
+}
+
+// toCropCategoryResponse converts a CropCategory model to CropCategoryResponse
+func toCropCategoryResponse(category models.CropCategory) CropCategoryResponse {
+	return CropCategoryResponse{
+		ID:   category.ID,
+		Slug: category.Slug,
+		Name: category.Name,
+	}
+}
+
+// toCropResponse converts a Crop model, with its category, varieties and synonyms preloaded, to CropResponse
+func toCropResponse(crop models.Crop) CropResponse {
+	varieties := make([]CropVarietyResponse, len(crop.Varieties))
+	for i, v := range crop.Varieties {
+		varieties[i] = CropVarietyResponse{ID: v.ID, Name: v.Name}
+	}
+	synonyms := make([]CropSynonymResponse, len(crop.Synonyms))
+	for i, s := range crop.Synonyms {
+		synonyms[i] = CropSynonymResponse{ID: s.ID, Language: s.Language, Term: s.Term}
+	}
+
+	return CropResponse{
+		ID:        crop.ID,
+		Slug:      crop.Slug,
+		Name:      crop.Name,
+		Category:  toCropCategoryResponse(crop.Category),
+		Varieties: varieties,
+		Synonyms:  synonyms,
+	}
+}
+
+// preloadCrop loads everything toCropResponse needs
+func preloadCrop(db *gorm.DB) *gorm.DB {
+	return db.Preload("Category").
+		Preload("Varieties", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC") }).
+		Preload("Synonyms", func(db *gorm.DB) *gorm.DB { return db.Order("language ASC, term ASC") })
+}
+
+// GetCropCategories handles GET /api/v1/catalog/categories (public)
+func GetCropCategories(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	var categories []models.CropCategory
+	if err := db.Order("name ASC").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+
+	responses := make([]CropCategoryResponse, len(categories))
+	for i, category := range categories {
+		responses[i] = toCropCategoryResponse(category)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetCrops handles GET /api/v1/catalog/crops (public).
+// Filters by category slug and by q, which matches names, varieties and synonyms.
+func GetCrops(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	query := preloadCrop(db).Model(&models.Crop{})
+	if category := c.Query("category"); category != "" {
+		cropIDs, err := catalog.CategoryCropIDs(db, category)
+		if err != nil {
+			if errors.Is(err, catalog.ErrCategoryNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown category " + strconv.Quote(category)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch crops"})
+			return
+		}
+		query = query.Where("id IN ?", cropIDs)
+	}
+	if q := c.Query("q"); q != "" {
+		cropIDs, err := catalog.MatchCropIDs(db, q)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch crops"})
+			return
+		}
+		query = query.Where("id IN ?", cropIDs)
+	}
+
+	var crops []models.Crop
+	if err := query.Order("name ASC").Find(&crops).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch crops"})
+		return
+	}
+
+	responses := make([]CropResponse, len(crops))
+	for i, crop := range crops {
+		responses[i] = toCropResponse(crop)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetCrop handles GET /api/v1/catalog/crops/:id (public)
+func GetCrop(c *gin.Context) {
+	respondWithCrop(c, http.StatusOK, c.Param("id"))
+}
+
+// GetCatalogLanguages handles GET /api/v1/catalog/languages (public)
+func GetCatalogLanguages(c *gin.Context) {
+	type language struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+
+	languages := make([]language, 0, len(catalog.Languages))
+	for code, name := range catalog.Languages {
+		languages = append(languages, language{Code: code, Name: name})
+	}
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Code < languages[j].Code })
+
+	c.JSON(http.StatusOK, languages)
+}
+
+// AdminCreateCropCategory handles POST /api/v1/admin/catalog/categories
+func AdminCreateCropCategory(c *gin.Context) {
+	var req CropCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	category := models.CropCategory{
+		Slug: catalog.Slugify(req.Slug),
+		Name: catalog.NormalizeTerm(req.Name),
+	}
+	if category.Slug == "" {
+		category.Slug = catalog.Slugify(req.Name)
+	}
+	if category.Slug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slug must contain latin letters or digits"})
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.CropCategory{}).Where("slug = ?", category.Slug).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return catalog.ErrDuplicate
+		}
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionCategoryCreate, audit.TargetCategory, category.ID, gin.H{"slug": category.Slug, "name": category.Name}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to create category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, toCropCategoryResponse(category))
+}
+
+// AdminUpdateCropCategory handles PUT /api/v1/admin/catalog/categories/:id.
+// Renames a category; its slug never changes because clients filter by it.
+func AdminUpdateCropCategory(c *gin.Context) {
+	var req CropCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	var category models.CropCategory
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", c.Param("id")).First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return catalog.ErrCategoryNotFound
+			}
+			return err
+		}
+
+		previous := category.Name
+		category.Name = catalog.NormalizeTerm(req.Name)
+		if err := tx.Model(&category).Update("name", category.Name).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionCategoryUpdate, audit.TargetCategory, category.ID, gin.H{"from": previous, "to": category.Name}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to update category")
+		return
+	}
+	reindexProducts(c, "crop_id IN (?)", db.Model(&models.Crop{}).Select("id").Where("category_id = ?", category.ID))
+
+	c.JSON(http.StatusOK, toCropCategoryResponse(category))
+}
+
+// AdminDeleteCropCategory handles DELETE /api/v1/admin/catalog/categories/:id.
+// Only empty categories can be deleted.
+func AdminDeleteCropCategory(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var category models.CropCategory
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", c.Param("id")).First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return catalog.ErrCategoryNotFound
+			}
+			return err
+		}
+
+		var crops int64
+		if err := tx.Model(&models.Crop{}).Where("category_id = ?", category.ID).Count(&crops).Error; err != nil {
+			return err
+		}
+		if crops > 0 {
+			return catalog.ErrCategoryInUse
+		}
+
+		if err := tx.Delete(&category).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionCategoryDelete, audit.TargetCategory, category.ID, gin.H{"slug": category.Slug, "name": category.Name}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to delete category")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+// AdminCreateCrop handles POST /api/v1/admin/catalog/crops.
+// Creates a crop together with its initial varieties and synonyms.
+func AdminCreateCrop(c *gin.Context) {
+	var req CreateCropRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	crop := models.Crop{Name: catalog.NormalizeTerm(req.Name)}
+	crop.Slug = catalog.Slugify(crop.Name)
+	if crop.Slug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidCropName.Error()})
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		categoryID, err := findCategoryID(tx, req.Category)
+		if err != nil {
+			return err
+		}
+		crop.CategoryID = categoryID
+
+		var count int64
+		if err := tx.Model(&models.Crop{}).Where("name = ? OR slug = ?", crop.Name, crop.Slug).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return catalog.ErrDuplicate
+		}
+		if err := tx.Create(&crop).Error; err != nil {
+			return err
+		}
+
+		for _, name := range req.Varieties {
+			if err := addCropVariety(tx, crop.ID, name); err != nil {
+				return err
+			}
+		}
+		for _, synonym := range req.Synonyms {
+			if _, err := addCropSynonym(tx, crop.ID, synonym); err != nil {
+				return err
+			}
+		}
+
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionCropCreate, audit.TargetCrop, crop.ID, gin.H{"name": crop.Name, "category": req.Category}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to create crop")
+		return
+	}
+
+	respondWithCrop(c, http.StatusCreated, crop.ID)
+}
+
+// AdminUpdateCrop handles PUT /api/v1/admin/catalog/crops/:id
+func AdminUpdateCrop(c *gin.Context) {
+	var req UpdateCropRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name == nil && req.Category == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	cropID := c.Param("id")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		crop, err := lockCrop(tx, cropID)
+		if err != nil {
+			return err
+		}
+
+		updates := make(map[string]interface{})
+		details := gin.H{}
+		if req.Name != nil {
+			name := catalog.NormalizeTerm(*req.Name)
+			slug := catalog.Slugify(name)
+			if slug == "" {
+				return errInvalidCropName
+			}
+			var count int64
+			if err := tx.Model(&models.Crop{}).Where("(name = ? OR slug = ?) AND id <> ?", name, slug, crop.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return catalog.ErrDuplicate
+			}
+			updates["name"] = name
+			updates["slug"] = slug
+			details["name"] = gin.H{"from": crop.Name, "to": name}
+		}
+		if req.Category != nil {
+			categoryID, err := findCategoryID(tx, *req.Category)
+			if err != nil {
+				return err
+			}
+			updates["category_id"] = categoryID
+			details["category"] = *req.Category
+		}
+
+		if err := tx.Model(&crop).Updates(updates).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionCropUpdate, audit.TargetCrop, crop.ID, details))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to update crop")
+		return
+	}
+	reindexProducts(c, "crop_id = ?", cropID)
+
+	respondWithCrop(c, http.StatusOK, cropID)
+}
+
+// AdminDeleteCrop handles DELETE /api/v1/admin/catalog/crops/:id.
+// Listings linked to the crop keep their name and are unlinked.
+func AdminDeleteCrop(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	var productIDs []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		crop, err := lockCrop(tx, c.Param("id"))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Product{}).Where("crop_id = ?", crop.ID).Pluck("id", &productIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Product{}).Where("crop_id = ?", crop.ID).
+			Updates(map[string]interface{}{"crop_id": nil, "variety_id": nil}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&crop).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionCropDelete, audit.TargetCrop, crop.ID, gin.H{"name": crop.Name}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to delete crop")
+		return
+	}
+	if len(productIDs) > 0 {
+		reindexProducts(c, "id IN ?", productIDs)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Crop deleted successfully"})
+}
+
+// AdminAddCropVariety handles POST /api/v1/admin/catalog/crops/:id/varieties
+func AdminAddCropVariety(c *gin.Context) {
+	var req CropVarietyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	cropID := c.Param("id")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		crop, err := lockCrop(tx, cropID)
+		if err != nil {
+			return err
+		}
+		if err := addCropVariety(tx, crop.ID, req.Name); err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionVarietyAdd, audit.TargetCrop, crop.ID, gin.H{"variety": catalog.NormalizeTerm(req.Name)}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to add variety")
+		return
+	}
+
+	respondWithCrop(c, http.StatusCreated, cropID)
+}
+
+// AdminDeleteCropVariety handles DELETE /api/v1/admin/catalog/crops/:id/varieties/:variety_id.
+// Listings of the variety stay linked to the crop.
+func AdminDeleteCropVariety(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	cropID := c.Param("id")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		crop, err := lockCrop(tx, cropID)
+		if err != nil {
+			return err
+		}
+
+		var variety models.CropVariety
+		if err := tx.Where("id = ? AND crop_id = ?", c.Param("variety_id"), crop.ID).First(&variety).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return catalog.ErrVarietyNotFound
+			}
+			return err
+		}
+
+		if err := tx.Model(&models.Product{}).Where("variety_id = ?", variety.ID).Update("variety_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&variety).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionVarietyRemove, audit.TargetCrop, crop.ID, gin.H{"variety": variety.Name}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to remove variety")
+		return
+	}
+	reindexProducts(c, "crop_id = ?", cropID)
+
+	respondWithCrop(c, http.StatusOK, cropID)
+}
+
+// AdminAddCropSynonym handles POST /api/v1/admin/catalog/crops/:id/synonyms
+func AdminAddCropSynonym(c *gin.Context) {
+	var req CropSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	cropID := c.Param("id")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		crop, err := lockCrop(tx, cropID)
+		if err != nil {
+			return err
+		}
+		synonym, err := addCropSynonym(tx, crop.ID, req)
+		if err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionSynonymAdd, audit.TargetCrop, crop.ID, gin.H{"language": synonym.Language, "term": synonym.Term}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to add synonym")
+		return
+	}
+	reindexProducts(c, "crop_id = ?", cropID)
+
+	respondWithCrop(c, http.StatusCreated, cropID)
+}
+
+// AdminDeleteCropSynonym handles DELETE /api/v1/admin/catalog/crops/:id/synonyms/:synonym_id
+func AdminDeleteCropSynonym(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	cropID := c.Param("id")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		crop, err := lockCrop(tx, cropID)
+		if err != nil {
+			return err
+		}
+
+		var synonym models.CropSynonym
+		if err := tx.Where("id = ? AND crop_id = ?", c.Param("synonym_id"), crop.ID).First(&synonym).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return catalog.ErrSynonymNotFound
+			}
+			return err
+		}
+
+		if err := tx.Delete(&synonym).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, catalogAuditEntry(c, audit.ActionSynonymRemove, audit.TargetCrop, crop.ID, gin.H{"language": synonym.Language, "term": synonym.Term}))
+	})
+	if err != nil {
+		respondCatalogError(c, err, "Failed to remove synonym")
+		return
+	}
+	reindexProducts(c, "crop_id = ?", cropID)
+
+	respondWithCrop(c, http.StatusOK, cropID)
+}
+
+// catalogAuditEntry builds an audit entry for a catalog change by the current admin
+func catalogAuditEntry(c *gin.Context, action, targetType, targetID string, details gin.H) audit.Entry {
+	return audit.Entry{
+		ActorID:    c.MustGet("user_id").(string),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+		IPAddress:  c.ClientIP(),
+	}
+}
+
+// findCategoryID resolves a category slug to its ID
+func findCategoryID(tx *gorm.DB, slug string) (string, error) {
+	var category models.CropCategory
+	if err := tx.Where("slug = ?", slug).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", catalog.ErrCategoryNotFound
+		}
+		return "", err
+	}
+	return category.ID, nil
+}
+
+// lockCrop loads a crop for update so concurrent edits to it are serialised
+func lockCrop(tx *gorm.DB, cropID string) (models.Crop, error) {
+	var crop models.Crop
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", cropID).First(&crop).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return crop, catalog.ErrCropNotFound
+		}
+		return crop, err
+	}
+	return crop, nil
+}
+
+// addCropVariety adds a variety to a crop, rejecting duplicates
+func addCropVariety(tx *gorm.DB, cropID, name string) error {
+	variety := models.CropVariety{CropID: cropID, Name: catalog.NormalizeTerm(name)}
+
+	var count int64
+	if err := tx.Model(&models.CropVariety{}).Where("crop_id = ? AND name = ?", cropID, variety.Name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return catalog.ErrDuplicate
+	}
+	return tx.Create(&variety).Error
+}
+
+// addCropSynonym adds a synonym to a crop, rejecting unknown languages and duplicates
+func addCropSynonym(tx *gorm.DB, cropID string, req CropSynonymRequest) (models.CropSynonym, error) {
+	synonym := models.CropSynonym{CropID: cropID, Language: req.Language, Term: catalog.NormalizeTerm(req.Term)}
+	if _, ok := catalog.Languages[synonym.Language]; !ok {
+		return synonym, catalog.ErrUnknownLanguage
+	}
+
+	var count int64
+	if err := tx.Model(&models.CropSynonym{}).
+		Where("crop_id = ? AND language = ? AND term = ?", cropID, synonym.Language, synonym.Term).
+		Count(&count).Error; err != nil {
+		return synonym, err
+	}
+	if count > 0 {
+		return synonym, catalog.ErrDuplicate
+	}
+	return synonym, tx.Create(&synonym).Error
+}
+
+// respondWithCrop loads a crop with its category, varieties and synonyms and writes it as the response
+func respondWithCrop(c *gin.Context, status int, cropID string) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	var crop models.Crop
+	if err := preloadCrop(db).Where("id = ?", cropID).First(&crop).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crop not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch crop"})
+		return
+	}
+
+	c.JSON(status, toCropResponse(crop))
+}
+
+// respondCatalogError maps a crop catalog failure to an HTTP response
+func respondCatalogError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, catalog.ErrCategoryNotFound), errors.Is(err, catalog.ErrCropNotFound),
+		errors.Is(err, catalog.ErrVarietyNotFound), errors.Is(err, catalog.ErrSynonymNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, catalog.ErrDuplicate), errors.Is(err, catalog.ErrCategoryInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, catalog.ErrUnknownLanguage), errors.Is(err, errInvalidCropName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CheckoutItemRequest represents a single cart line in a checkout request
+type CheckoutItemRequest struct {
+	ProductID string  `json:"product_id" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// CheckoutRequest represents the request payload for checking out a cart
+type CheckoutRequest struct {
+	Items        []CheckoutItemRequest `json:"items" binding:"required,min=1,dive"`
+	DeliveryMode string                `json:"delivery_mode" binding:"required,oneof=pickup courier"`
+}
+
+// CheckoutResponse represents the result of a checkout: one order per farmer
+type CheckoutResponse struct {
+	CheckoutID  string          `json:"checkout_id"`
+	TotalAmount money.Paise     `json:"total_amount"`
+	ItemCount   int             `json:"item_count"`
+	Orders      []OrderResponse `json:"orders"`
+}
+
+// mergeCartItems collapses duplicate product lines, preserving first-seen order
+func mergeCartItems(items []CheckoutItemRequest) ([]string, map[string]float64) {
+	productIDs := make([]string, 0, len(items))
+	quantities := make(map[string]float64, len(items))
+	for _, item := range items {
+		if _, seen := quantities[item.ProductID]; !seen {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+	return productIDs, quantities
+}
+
+// Checkout handles POST /api/v1/orders/checkout (buyer only)
+func Checkout(c *gin.Context) {
+	var req CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	buyerID := c.MustGet("user_id").(string)
+
+	productIDs, quantities := mergeCartItems(req.Items)
+	checkoutID := uuid.NewString()
+
+	// Lock, validate and price every line, then write all orders and stock holds atomically
+	var orderIDs []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		orderIDs, err = placeOrders(tx, buyerID, req.DeliveryMode, checkoutID, productIDs, quantities)
+		return err
+	})
+	if err != nil {
+		respondOrderPlacementError(c, err, "Failed to create orders")
+		return
+	}
+
+	// Load orders with items for response, in farmer grouping order
+	var createdOrders []models.Order
+	if err := db.Preload("OrderItems").Where("checkout_id = ?", checkoutID).Find(&createdOrders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch created orders"})
+		return
+	}
+	ordersByID := make(map[string]models.Order, len(createdOrders))
+	for _, order := range createdOrders {
+		ordersByID[order.ID] = order
+	}
+
+	response := CheckoutResponse{
+		CheckoutID: checkoutID,
+		ItemCount:  len(productIDs),
+		Orders:     make([]OrderResponse, 0, len(orderIDs)),
+	}
+	for _, id := range orderIDs {
+		order := ordersByID[id]
+		// placeOrders already rejected checkouts whose total overflows
+		response.TotalAmount, _ = money.Add(response.TotalAmount, order.TotalAmount)
+		response.Orders = append(response.Orders, toOrderResponse(order))
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetCheckout handles GET /api/v1/orders/checkout/:checkout_id (buyer only)
+func GetCheckout(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	buyerID := c.MustGet("user_id").(string)
+	checkoutID := c.Param("checkout_id")
+
+	var orders []models.Order
+	if err := db.Preload("OrderItems").Where("checkout_id = ? AND buyer_id = ?", checkoutID, buyerID).Order("created_at ASC").Find(&orders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
+		return
+	}
+	if len(orders) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checkout not found"})
+		return
+	}
+
+	response := CheckoutResponse{
+		CheckoutID: checkoutID,
+		Orders:     make([]OrderResponse, len(orders)),
+	}
+	for i, order := range orders {
+		total, err := money.Add(response.TotalAmount, order.TotalAmount)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Checkout total is too large"})
+			return
+		}
+		response.TotalAmount = total
+		response.ItemCount += len(order.OrderItems)
+		response.Orders[i] = toOrderResponse(order)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FarmerStorefrontResponse represents a farmer's public storefront. It only
+// carries public farm details; phone numbers, addresses and other PII are omitted.
+type FarmerStorefrontResponse struct {
+	FarmerID           string            `json:"farmer_id"`
+	Name               string            `json:"name"`
+	FarmName           string            `json:"farm_name"`
+	City               string            `json:"city"`
+	State              string            `json:"state"`
+	FarmSizeAcres      float64           `json:"farm_size_acres"`
+	Rating             float64           `json:"rating"`
+	TotalOrders        int               `json:"total_orders"`
+	IsVerified         bool              `json:"is_verified"`
+	ActiveProductCount int64             `json:"active_product_count"`
+	Products           []ProductResponse `json:"products,omitempty"`
+}
+
+// toFarmerStorefrontResponse converts a FarmerProfile (with User loaded) to FarmerStorefrontResponse
+func toFarmerStorefrontResponse(p models.FarmerProfile, activeProductCount int64) FarmerStorefrontResponse {
+	return FarmerStorefrontResponse{
+		FarmerID:           p.FarmerID,
+		Name:               p.User.Name,
+		FarmName:           p.FarmName,
+		City:               p.City,
+		State:              p.State,
+		FarmSizeAcres:      p.FarmSizeAcres,
+		Rating:             p.Rating,
+		TotalOrders:        p.TotalOrders,
+		IsVerified:         p.User.IsVerified,
+		ActiveProductCount: activeProductCount,
+	}
+}
+
+// activeFarmerProfiles returns a query over profiles of active farmer accounts
+func activeFarmerProfiles(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.FarmerProfile{}).
+		Joins("User").
+		Where("`User`.role = ? AND `User`.is_active = ?", "farmer", true)
+}
+
+// GetFarmers handles GET /api/v1/farmers (public with filters)
+func GetFarmers(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	query := activeFarmerProfiles(db)
+
+	// Apply filters
+	if pincode := c.Query("pincode"); pincode != "" {
+		query = query.Where("farmer_profiles.pincode = ?", pincode)
+	}
+	if state := c.Query("state"); state != "" {
+		query = query.Where("farmer_profiles.state = ?", state)
+	}
+	if minRating := c.Query("min_rating"); minRating != "" {
+		if min, err := strconv.ParseFloat(minRating, 64); err == nil {
+			query = query.Where("farmer_profiles.rating >= ?", min)
+		}
+	}
+
+	// Best rated farmers first
+	query = query.Order("farmer_profiles.rating DESC, farmer_profiles.total_orders DESC")
+
+	var profiles []models.FarmerProfile
+	if err := query.Find(&profiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch farmers"})
+		return
+	}
+
+	// Count active products per farmer in a single query
+	farmerIDs := make([]string, len(profiles))
+	for i, p := range profiles {
+		farmerIDs[i] = p.FarmerID
+	}
+	counts := make(map[string]int64, len(profiles))
+	if len(farmerIDs) > 0 {
+		var rows []struct {
+			FarmerID string
+			Count    int64
+		}
+		if err := db.Model(&models.Product{}).
+			Select("farmer_id, COUNT(*) AS count").
+			Where("farmer_id IN ? AND status = ?", farmerIDs, "active").
+			Group("farmer_id").
+			Scan(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch farmers"})
+			return
+		}
+		for _, row := range rows {
+			counts[row.FarmerID] = row.Count
+		}
+	}
+
+	responses := make([]FarmerStorefrontResponse, len(profiles))
+	for i, p := range profiles {
+		responses[i] = toFarmerStorefrontResponse(p, counts[p.FarmerID])
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetFarmer handles GET /api/v1/farmers/:id (public storefront)
+func GetFarmer(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	farmerID := c.Param("id")
+
+	var profile models.FarmerProfile
+	if err := activeFarmerProfiles(db).Where("farmer_profiles.farmer_id = ?", farmerID).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Farmer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var products []models.Product
+	if err := db.Where("farmer_id = ? AND status = ?", farmerID, "active").Order("created_at DESC").Find(&products).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+		return
+	}
+
+	response := toFarmerStorefrontResponse(profile, int64(len(products)))
+	response.Products = make([]ProductResponse, len(products))
+	for i, p := range products {
+		response.Products[i] = toProductResponse(p)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -1,8 +1,18 @@
 package handlers
 
-import "github.com/gin-gonic/gin"
+import (
+	"farmer-to-buyer-portal/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
 
 // Health responds with service liveness.
 func Health(c *gin.Context) {
 	c.JSON(200, gin.H{"status": "ok"})
 }
+
+// JWKS publishes the public keys used to verify access tokens.
+func JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(200, utils.JWKS())
+}
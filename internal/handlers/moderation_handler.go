@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/moderation"
+	"farmer-to-buyer-portal/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ModerateProductRequest represents the request payload for a moderation decision
+type ModerateProductRequest struct {
+	Action string `json:"action" binding:"required,oneof=flag hide remove restore"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// moderationQueueSorts are the orderings the moderation queue accepts; the
+// first is the default. Every moderated listing has moderated_at set.
+var moderationQueueSorts = []pagination.Sort{
+	{Name: "oldest", Column: "moderated_at", Kind: pagination.KindTime},
+	{Name: "newest", Column: "moderated_at", Kind: pagination.KindTime, Desc: true},
+}
+
+// AdminGetModerationQueue handles GET /api/v1/admin/products/moderation.
+// Lists flagged and hidden listings, oldest decision first; pass state to pick
+// one of flagged, hidden or removed. Pages with limit, cursor and sort.
+func AdminGetModerationQueue(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	states := []string{moderation.StateFlagged, moderation.StateHidden}
+	if state := c.Query("state"); state != "" {
+		switch state {
+		case moderation.StateFlagged, moderation.StateHidden, moderation.StateRemoved:
+			states = []string{state}
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "state must be one of flagged, hidden, removed"})
+			return
+		}
+	}
+
+	query := db.Model(&models.Product{}).Where("moderation_state IN ?", states)
+	query, listing, ok := startAdminListing(c, query, moderationQueueSorts, "Failed to fetch moderation queue")
+	if !ok {
+		return
+	}
+
+	var products []models.Product
+	if err := query.Find(&products).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation queue"})
+		return
+	}
+
+	page, n := listing.page(len(products), func(i int) (interface{}, string) {
+		return *products[i].ModeratedAt, products[i].ID
+	})
+	responses := make([]ProductResponse, n)
+	for i, p := range products[:n] {
+		responses[i] = toOwnerProductResponse(p)
+	}
+
+	c.JSON(http.StatusOK, ProductListResponse{Items: responses, Page: page})
+}
+
+// AdminModerateProduct handles POST /api/v1/admin/products/:id/moderation.
+// Flags, hides, removes or restores a listing and notifies its farmer.
+func AdminModerateProduct(c *gin.Context) {
+	var req ModerateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	decision := moderation.Request{
+		Action:    req.Action,
+		Reason:    req.Reason,
+		ActorID:   c.MustGet("user_id").(string),
+		IPAddress: c.ClientIP(),
+	}
+
+	var product models.Product
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		product, err = moderation.Apply(tx, c.Param("id"), decision)
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, moderation.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		case errors.Is(err, moderation.ErrInvalidAction), errors.Is(err, moderation.ErrReasonRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, moderation.ErrNotModerated):
+			c.JSON(http.StatusConflict, gin.H{"error": "Product is not under moderation"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate product"})
+		}
+		return
+	}
+
+	reindexProducts(c, "id = ?", product.ID)
+	moderation.NotifyFarmer(product, decision)
+
+	c.JSON(http.StatusOK, toOwnerProductResponse(product))
+}
@@ -4,10 +4,16 @@ import (
 	"errors"
 	"net/http"
 
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/inventory"
 	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/money"
+	"farmer-to-buyer-portal/internal/orderstate"
+	"farmer-to-buyer-portal/internal/pagination"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CreateOrderRequest represents the request payload for creating an order
@@ -20,43 +26,74 @@ type CreateOrderRequest struct {
 // UpdateOrderStatusRequest represents the request payload for updating order status
 type UpdateOrderStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=accepted rejected shipped delivered"`
+	Note   string `json:"note" binding:"max=500"`
 }
 
 // OrderItemResponse represents an order item in API responses
 type OrderItemResponse struct {
-	ID           string  `json:"id"`
-	ProductID    string  `json:"product_id"`
-	Quantity     float64 `json:"quantity"`
-	PricePerUnit float64 `json:"price_per_unit"`
-	CreatedAt    string  `json:"created_at"`
-	UpdatedAt    string  `json:"updated_at"`
+	ID           string      `json:"id"`
+	ProductID    string      `json:"product_id"`
+	Quantity     float64     `json:"quantity"`
+	PricePerUnit money.Paise `json:"price_per_unit"`
+	LineTotal    money.Paise `json:"line_total"`
+	CreatedAt    string      `json:"created_at"`
+	UpdatedAt    string      `json:"updated_at"`
+}
+
+// OrderStatusEventResponse represents a single entry in an order's status timeline
+type OrderStatusEventResponse struct {
+	ActorID    string `json:"actor_id,omitempty"`
+	ActorRole  string `json:"actor_role"`
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status"`
+	Note       string `json:"note,omitempty"`
+	CreatedAt  string `json:"created_at"`
 }
 
 // OrderResponse represents an order in API responses
 type OrderResponse struct {
-	ID           string             `json:"id"`
-	BuyerID      string             `json:"buyer_id"`
-	FarmerID     string             `json:"farmer_id"`
-	Status       string             `json:"status"`
-	DeliveryMode string             `json:"delivery_mode"`
-	TotalAmount  float64            `json:"total_amount"`
-	CreatedAt    string             `json:"created_at"`
-	UpdatedAt    string             `json:"updated_at"`
-	OrderItems   []OrderItemResponse `json:"order_items"`
+	ID                    string                     `json:"id"`
+	BuyerID               string                     `json:"buyer_id"`
+	FarmerID              string                     `json:"farmer_id"`
+	CheckoutID            string                     `json:"checkout_id,omitempty"`
+	Status                string                     `json:"status"`
+	DeliveryMode          string                     `json:"delivery_mode"`
+	TotalAmount           money.Paise                `json:"total_amount"`
+	CreatedAt             string                     `json:"created_at"`
+	UpdatedAt             string                     `json:"updated_at"`
+	CancellationRequested bool                       `json:"cancellation_requested"`
+	CancellationReason    string                     `json:"cancellation_reason,omitempty"`
+	OrderItems            []OrderItemResponse        `json:"order_items"`
+	Timeline              []OrderStatusEventResponse `json:"timeline,omitempty"`
 }
 
 // toOrderItemResponse converts an OrderItem model to OrderItemResponse
 func toOrderItemResponse(oi models.OrderItem) OrderItemResponse {
+	// Placement rejects lines whose total overflows, so stored items always fit
+	lineTotal, _ := money.MulQuantity(oi.PricePerUnit, oi.Quantity)
 	return OrderItemResponse{
 		ID:           oi.ID,
 		ProductID:    oi.ProductID,
 		Quantity:     oi.Quantity,
 		PricePerUnit: oi.PricePerUnit,
+		LineTotal:    lineTotal,
 		CreatedAt:    oi.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:    oi.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
 
+// toOrderStatusEventResponse converts an OrderStatusEvent model to OrderStatusEventResponse
+func toOrderStatusEventResponse(e models.OrderStatusEvent) OrderStatusEventResponse {
+	return OrderStatusEventResponse{
+		ActorID:    e.ActorID,
+		ActorRole:  e.ActorRole,
+		FromStatus: e.FromStatus,
+		ToStatus:   e.ToStatus,
+		Note:       e.Note,
+		CreatedAt:  e.CreatedAt.Format("2006-01-02T15:04:05.000Z07:00"),
+	}
+}
+
 // toOrderResponse converts an Order model to OrderResponse
 func toOrderResponse(order models.Order) OrderResponse {
 	items := make([]OrderItemResponse, len(order.OrderItems))
@@ -64,28 +101,30 @@ func toOrderResponse(order models.Order) OrderResponse {
 		items[i] = toOrderItemResponse(item)
 	}
 
+	var timeline []OrderStatusEventResponse
+	for _, event := range order.StatusEvents {
+		timeline = append(timeline, toOrderStatusEventResponse(event))
+	}
+
 	return OrderResponse{
-		ID:           order.ID,
-		BuyerID:      order.BuyerID,
-		FarmerID:     order.FarmerID,
-		Status:       order.Status,
-		DeliveryMode: order.DeliveryMode,
-		TotalAmount:  order.TotalAmount,
-		CreatedAt:    order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:    order.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		OrderItems:   items,
+		ID:                    order.ID,
+		BuyerID:               order.BuyerID,
+		FarmerID:              order.FarmerID,
+		CheckoutID:            order.CheckoutID,
+		Status:                order.Status,
+		DeliveryMode:          order.DeliveryMode,
+		TotalAmount:           order.TotalAmount,
+		CreatedAt:             order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:             order.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CancellationRequested: order.CancellationRequested,
+		CancellationReason:    order.CancellationReason,
+		OrderItems:            items,
+		Timeline:              timeline,
 	}
 }
 
 // CreateOrder handles POST /api/v1/orders (buyer only)
 func CreateOrder(c *gin.Context) {
-	// Check if user is buyer
-	role := c.MustGet("role").(string)
-	if role != "buyer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only buyers can place orders"})
-		return
-	}
-
 	var req CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -95,58 +134,21 @@ func CreateOrder(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	buyerID := c.MustGet("user_id").(string)
 
-	// Fetch product
-	var product models.Product
-	if err := db.Where("id = ?", req.ProductID).First(&product).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-		return
-	}
-
-	// Check if product is active
-	if product.Status != "active" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Product is not available for ordering"})
-		return
-	}
-
-	// Calculate total amount
-	totalAmount := req.Quantity * product.PricePerUnit
-
-	// Create order
-	order := models.Order{
-		BuyerID:      buyerID,
-		FarmerID:     product.FarmerID,
-		Status:       "pending",
-		DeliveryMode: req.DeliveryMode,
-		TotalAmount:  totalAmount,
-	}
-
-	if err := db.Create(&order).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order"})
-		return
-	}
-
-	// Create order item
-	orderItem := models.OrderItem{
-		OrderID:      order.ID,
-		ProductID:    req.ProductID,
-		Quantity:     req.Quantity,
-		PricePerUnit: product.PricePerUnit,
-	}
-
-	if err := db.Create(&orderItem).Error; err != nil {
-		// Rollback order creation
-		db.Delete(&order)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order item"})
+	// Lock the product, check stock, write the order and reserve stock atomically
+	var orderIDs []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		orderIDs, err = placeOrders(tx, buyerID, req.DeliveryMode, "", []string{req.ProductID}, map[string]float64{req.ProductID: req.Quantity})
+		return err
+	})
+	if err != nil {
+		respondOrderPlacementError(c, err, "Failed to create order")
 		return
 	}
 
 	// Load order with items for response
 	var createdOrder models.Order
-	if err := db.Preload("OrderItems").Where("id = ?", order.ID).First(&createdOrder).Error; err != nil {
+	if err := db.Preload("OrderItems").Where("id = ?", orderIDs[0]).First(&createdOrder).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch created order"})
 		return
 	}
@@ -154,7 +156,7 @@ func CreateOrder(c *gin.Context) {
 	c.JSON(http.StatusCreated, toOrderResponse(createdOrder))
 }
 
-// GetOrder handles GET /api/v1/orders/:id (buyer or farmer can access own order)
+// GetOrder handles GET /api/v1/orders/:id (buyer or farmer can access own order, admins any order)
 func GetOrder(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	orderID := c.Param("id")
@@ -162,14 +164,21 @@ func GetOrder(c *gin.Context) {
 	role := c.MustGet("role").(string)
 
 	var order models.Order
-	query := db.Preload("OrderItems").Where("id = ?", orderID)
+	query := db.Preload("OrderItems").
+		Preload("StatusEvents", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("id = ?", orderID)
 
 	// Check ownership based on role
-	if role == "buyer" {
+	switch role {
+	case authz.RoleBuyer:
 		query = query.Where("buyer_id = ?", userID)
-	} else if role == "farmer" {
+	case authz.RoleFarmer:
 		query = query.Where("farmer_id = ?", userID)
-	} else {
+	case authz.RoleAdmin:
+		// Admins can read any order
+	default:
 		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid role"})
 		return
 	}
@@ -188,145 +197,158 @@ func GetOrder(c *gin.Context) {
 
 // GetBuyerOrders handles GET /api/v1/orders/buyer/me (buyer only)
 func GetBuyerOrders(c *gin.Context) {
-	// Check if user is buyer
-	role := c.MustGet("role").(string)
-	if role != "buyer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only buyers can access this endpoint"})
-		return
-	}
-
 	db := c.MustGet("db").(*gorm.DB)
 	buyerID := c.MustGet("user_id").(string)
 
-	var orders []models.Order
-	if err := db.Preload("OrderItems").Where("buyer_id = ?", buyerID).Order("created_at DESC").Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
-		return
-	}
-
-	responses := make([]OrderResponse, len(orders))
-	for i, order := range orders {
-		responses[i] = toOrderResponse(order)
-	}
-
-	c.JSON(http.StatusOK, responses)
+	respondWithOrderPage(c, db.Model(&models.Order{}).Where("buyer_id = ?", buyerID))
 }
 
 // GetFarmerOrders handles GET /api/v1/orders/farmer/me (farmer only)
 func GetFarmerOrders(c *gin.Context) {
-	// Check if user is farmer
-	role := c.MustGet("role").(string)
-	if role != "farmer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only farmers can access this endpoint"})
-		return
-	}
-
 	db := c.MustGet("db").(*gorm.DB)
 	farmerID := c.MustGet("user_id").(string)
 
-	var orders []models.Order
-	if err := db.Preload("OrderItems").Where("farmer_id = ?", farmerID).Order("created_at DESC").Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
-		return
-	}
-
-	responses := make([]OrderResponse, len(orders))
-	for i, order := range orders {
-		responses[i] = toOrderResponse(order)
-	}
-
-	c.JSON(http.StatusOK, responses)
+	respondWithOrderPage(c, db.Model(&models.Order{}).Where("farmer_id = ?", farmerID))
 }
 
 // UpdateOrderStatus handles PUT /api/v1/orders/:id/status (farmer only)
 func UpdateOrderStatus(c *gin.Context) {
-	// Check if user is farmer
-	role := c.MustGet("role").(string)
-	if role != "farmer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only farmers can update order status"})
-		return
-	}
-
 	db := c.MustGet("db").(*gorm.DB)
 	orderID := c.Param("id")
 	farmerID := c.MustGet("user_id").(string)
 
-	// Check if order exists and belongs to the farmer
-	var order models.Order
-	if err := db.Where("id = ? AND farmer_id = ?", orderID, farmerID).First(&order).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found or you don't have permission to update it"})
-			return
+	var req UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := orderstate.Run(db, func(tx *gorm.DB) error {
+		// Check if order exists and belongs to the farmer
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND farmer_id = ?", orderID, farmerID).
+			First(&order).Error; err != nil {
+			return err
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+
+		actor := orderstate.Actor{ID: farmerID, Role: orderstate.RoleFarmer}
+		return orderstate.Default.Transition(tx, &order, orderstate.State(req.Status), actor, req.Note)
+	})
+	if err != nil {
+		respondTransitionError(c, err, "Failed to update order status")
 		return
 	}
 
-	var req UpdateOrderStatusRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	respondWithOrder(c, orderID)
+}
+
+// orderSorts are the orderings order listings accept; the first is the default
+var orderSorts = []pagination.Sort{
+	{Name: "newest", Column: "created_at", Kind: pagination.KindTime, Desc: true},
+	{Name: "oldest", Column: "created_at", Kind: pagination.KindTime},
+	{Name: "total_asc", Column: "total_amount_paise", Kind: pagination.KindInt},
+	{Name: "total_desc", Column: "total_amount_paise", Kind: pagination.KindInt, Desc: true},
+}
+
+// OrderListResponse is one page of orders
+type OrderListResponse struct {
+	Items []OrderResponse `json:"items"`
+	Page  pagination.Page `json:"page"`
+}
+
+// respondWithOrderPage pages through the orders matched by query, optionally
+// narrowed by the status parameter, and writes the page as the response.
+// Without paging parameters it writes every order as a bare array.
+func respondWithOrderPage(c *gin.Context, query *gorm.DB) {
+	writeOrderPage(c, query, pagination.Requested(c.Query("limit"), c.Query("cursor"), c.Query("sort")))
+}
+
+// writeOrderPage is respondWithOrderPage with the choice between a page and a
+// bare array made by the caller
+func writeOrderPage(c *gin.Context, query *gorm.DB, paged bool) {
+	req, err := pagination.Parse(c.Query("limit"), c.Query("cursor"), c.Query("sort"), orderSorts)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
 
-	// Validate status transitions
-	currentStatus := order.Status
-	newStatus := req.Status
-
-	validTransition := false
-	switch currentStatus {
-	case "pending":
-		if newStatus == "accepted" || newStatus == "rejected" {
-			validTransition = true
-		}
-	case "accepted":
-		if newStatus == "shipped" {
-			validTransition = true
-		}
-	case "shipped":
-		if newStatus == "delivered" {
-			validTransition = true
+	var total int64
+	if paged {
+		if err := query.Count(&total).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
+			return
 		}
-	case "rejected", "delivered":
-		// Cannot transition from rejected or delivered
-		validTransition = false
+		query = req.Apply(query)
+	} else {
+		query = req.Order(query)
 	}
 
-	if !validTransition {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid status transition",
-			"current_status": currentStatus,
-			"requested_status": newStatus,
-			"valid_transitions": getValidTransitions(currentStatus),
-		})
+	var orders []models.Order
+	if err := query.Preload("OrderItems").Find(&orders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
 		return
 	}
 
-	// Update status
-	if err := db.Model(&order).Update("status", newStatus).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order status"})
-		return
+	n := len(orders)
+	var page pagination.Page
+	if paged {
+		page, n = req.Page(len(orders), total, func(i int) (interface{}, string) {
+			if req.Sort.Column == "total_amount_paise" {
+				return int64(orders[i].TotalAmount), orders[i].ID
+			}
+			return orders[i].CreatedAt, orders[i].ID
+		})
+	}
+	responses := make([]OrderResponse, n)
+	for i, order := range orders[:n] {
+		responses[i] = toOrderResponse(order)
 	}
 
-	// Reload order with items for response
-	var updatedOrder models.Order
-	if err := db.Preload("OrderItems").Where("id = ?", orderID).First(&updatedOrder).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated order"})
+	if !paged {
+		c.JSON(http.StatusOK, responses)
 		return
 	}
-
-	c.JSON(http.StatusOK, toOrderResponse(updatedOrder))
+	c.JSON(http.StatusOK, OrderListResponse{Items: responses, Page: page})
 }
 
-// getValidTransitions returns valid status transitions for a given status
-func getValidTransitions(status string) []string {
-	switch status {
-	case "pending":
-		return []string{"accepted", "rejected"}
-	case "accepted":
-		return []string{"shipped"}
-	case "shipped":
-		return []string{"delivered"}
+// respondTransitionError maps an order state machine failure to an HTTP response
+func respondTransitionError(c *gin.Context, err error, fallback string) {
+	var transitionErr *orderstate.TransitionError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found or you don't have permission to update it"})
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":             "Invalid status transition",
+			"current_status":    transitionErr.From,
+			"requested_status":  transitionErr.To,
+			"valid_transitions": transitionErr.Allowed,
+		})
+	case errors.Is(err, inventory.ErrReservationExpired):
+		c.JSON(http.StatusConflict, gin.H{"error": "Stock reservation for this order has expired"})
+	case errors.Is(err, orderstate.ErrCancellationPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "Resolve the buyer's cancellation request before shipping this order"})
+	case errors.Is(err, orderstate.ErrNoCancellationRequest):
+		c.JSON(http.StatusConflict, gin.H{"error": "There is no pending cancellation request for this order"})
 	default:
-		return []string{}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
 	}
 }
+
+// respondWithOrder reloads an order with its items and writes it as the response
+func respondWithOrder(c *gin.Context, orderID string) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	var order models.Order
+	if err := db.Preload("OrderItems").Where("id = ?", orderID).First(&order).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toOrderResponse(order))
+}
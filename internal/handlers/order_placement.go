@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/inventory"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/money"
+	"farmer-to-buyer-portal/internal/orderstate"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errOwnProduct is returned when a buyer tries to order their own listing
+var errOwnProduct = errors.New("you cannot order your own product")
+
+// farmerCart groups the locked products and quantities belonging to one farmer
+type farmerCart struct {
+	farmerID   string
+	products   []*models.Product
+	quantities []float64
+	total      money.Paise
+}
+
+// placeOrders locks every product, validates and prices every line, and only then
+// writes one order per farmer and reserves stock against it. It must run inside a transaction.
+// The created order IDs are returned in the order their farmers first appear.
+func placeOrders(tx *gorm.DB, buyerID, deliveryMode, checkoutID string, productIDs []string, quantities map[string]float64) ([]string, error) {
+	locked, err := inventory.LockProducts(tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate every line before anything is written
+	var carts []*farmerCart
+	cartsByFarmer := make(map[string]*farmerCart)
+	for _, productID := range productIDs {
+		product := locked[productID]
+		if product.FarmerID == buyerID {
+			return nil, &inventory.ProductError{ProductID: productID, Err: errOwnProduct}
+		}
+		if err := inventory.CheckAvailable(product, quantities[productID]); err != nil {
+			return nil, err
+		}
+
+		cart, ok := cartsByFarmer[product.FarmerID]
+		if !ok {
+			cart = &farmerCart{farmerID: product.FarmerID}
+			cartsByFarmer[product.FarmerID] = cart
+			carts = append(carts, cart)
+		}
+		cart.products = append(cart.products, product)
+		cart.quantities = append(cart.quantities, quantities[productID])
+	}
+
+	// Each line is rounded to the paisa once; order totals are their exact sum,
+	// and the checkout total, the sum of those, must fit as well
+	var checkoutTotal money.Paise
+	for _, cart := range carts {
+		for i, product := range cart.products {
+			lineTotal, err := money.MulQuantity(product.PricePerUnit, cart.quantities[i])
+			if err != nil {
+				return nil, &inventory.ProductError{ProductID: product.ID, Err: err}
+			}
+			if cart.total, err = money.Add(cart.total, lineTotal); err != nil {
+				return nil, &inventory.ProductError{ProductID: product.ID, Err: err}
+			}
+		}
+		if checkoutTotal, err = money.Add(checkoutTotal, cart.total); err != nil {
+			return nil, err
+		}
+	}
+
+	orderIDs := make([]string, 0, len(carts))
+	for _, cart := range carts {
+		order := models.Order{
+			BuyerID:      buyerID,
+			FarmerID:     cart.farmerID,
+			CheckoutID:   checkoutID,
+			Status:       string(orderstate.Pending),
+			DeliveryMode: deliveryMode,
+			TotalAmount:  cart.total,
+		}
+		if err := tx.Create(&order).Error; err != nil {
+			return nil, err
+		}
+		buyer := orderstate.Actor{ID: buyerID, Role: orderstate.RoleBuyer}
+		if err := orderstate.RecordEvent(tx, order.ID, buyer, "", orderstate.Pending, "Order placed"); err != nil {
+			return nil, err
+		}
+
+		for i, product := range cart.products {
+			orderItem := models.OrderItem{
+				OrderID:      order.ID,
+				ProductID:    product.ID,
+				Quantity:     cart.quantities[i],
+				PricePerUnit: product.PricePerUnit,
+			}
+			if err := tx.Create(&orderItem).Error; err != nil {
+				return nil, err
+			}
+			if err := inventory.Reserve(tx, product, order.ID, cart.quantities[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	return orderIDs, nil
+}
+
+// respondOrderPlacementError maps a placeOrders failure to an HTTP response
+func respondOrderPlacementError(c *gin.Context, err error, fallback string) {
+	var stockErr *inventory.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":              "Insufficient stock for " + stockErr.CropName,
+			"product_id":         stockErr.ProductID,
+			"requested_quantity": stockErr.Requested,
+			"available_quantity": stockErr.Available,
+		})
+		return
+	}
+
+	var productErr *inventory.ProductError
+	if errors.As(err, &productErr) {
+		switch {
+		case errors.Is(err, inventory.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found", "product_id": productErr.ProductID})
+		case errors.Is(err, inventory.ErrProductUnavailable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Product is not available for ordering", "product_id": productErr.ProductID})
+		case errors.Is(err, errOwnProduct):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot order your own product", "product_id": productErr.ProductID})
+		case errors.Is(err, money.ErrOutOfRange):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Order total is too large", "product_id": productErr.ProductID})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+		}
+		return
+	}
+
+	if errors.Is(err, money.ErrOutOfRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Checkout total is too large"})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/otp"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RequestOTPRequest represents the request payload for sending a verification code
+type RequestOTPRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// VerifyOTPRequest represents the request payload for verifying a phone number
+type VerifyOTPRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// RequestOTP handles POST /api/v1/auth/otp/request.
+// The response does not reveal whether the phone number is registered: unknown
+// numbers are rate limited like any other and get the same reply.
+func RequestOTP(c *gin.Context) {
+	var req RequestOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	err := otp.Request(db, req.Phone, otp.PurposeVerifyPhone)
+	respondCodeRequested(c, err, "If the phone number is registered, a verification code has been sent")
+}
+
+// VerifyOTP handles POST /api/v1/auth/otp/verify.
+// A correct code marks the phone as verified and logs the user in.
+func VerifyOTP(c *gin.Context) {
+	var req VerifyOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	if err := otp.Verify(db, req.Phone, otp.PurposeVerifyPhone, req.Code); err != nil {
+		respondOTPError(c, err, "Failed to verify code")
+		return
+	}
+
+	var user models.User
+	if err := db.Where("phone = ?", req.Phone).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired code"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	if !user.IsActive {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Account is deactivated"})
+		return
+	}
+
+	if user.MustResetPassword {
+		respondPasswordResetRequired(c)
+		return
+	}
+
+	if !user.IsVerified {
+		if err := db.Model(&user).Update("is_verified", true).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify phone number"})
+			return
+		}
+	}
+
+	respondWithToken(c, user)
+}
+
+// respondCodeRequested answers a public request for a code. Only a rate limit,
+// which applies to every number alike, changes the reply; other failures are
+// logged, since they can depend on whether the phone is registered.
+func respondCodeRequested(c *gin.Context, err error, message string) {
+	var rateLimit *otp.RateLimitError
+	if errors.As(err, &rateLimit) {
+		respondOTPError(c, err, message)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: failed to issue code: %v", err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// respondOTPError maps an OTP failure to an HTTP response
+func respondOTPError(c *gin.Context, err error, fallback string) {
+	var rateLimit *otp.RateLimitError
+	switch {
+	case errors.As(err, &rateLimit):
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":               "Too many code requests, please wait before trying again",
+			"retry_after_seconds": int(math.Ceil(rateLimit.RetryAfter.Seconds())),
+		})
+	case errors.Is(err, otp.ErrTooManyAttempts):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many incorrect attempts, please request a new code"})
+	case errors.Is(err, otp.ErrInvalidCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired code"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
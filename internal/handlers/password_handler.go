@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/otp"
+	"farmer-to-buyer-portal/internal/tokens"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ForgotPasswordRequest represents the request payload for starting a password reset
+type ForgotPasswordRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// ResetPasswordRequest represents the request payload for completing a password reset
+type ResetPasswordRequest struct {
+	Phone       string `json:"phone" binding:"required"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// ForgotPassword handles POST /api/v1/auth/password/forgot.
+// The response does not reveal whether the phone number is registered: unknown
+// numbers are rate limited like any other and get the same reply.
+func ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	err := otp.Request(db, req.Phone, otp.PurposePasswordReset)
+	respondCodeRequested(c, err, "If the phone number is registered, a reset code has been sent")
+}
+
+// ResetPassword handles POST /api/v1/auth/password/reset.
+// A correct code sets the new password and invalidates every token issued before.
+func ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+
+	if err := otp.Verify(db, req.Phone, otp.PurposePasswordReset, req.Code); err != nil {
+		respondOTPError(c, err, "Failed to verify reset code")
+		return
+	}
+
+	// Hash password
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Where("phone = ?", req.Phone).First(&user).Error; err != nil {
+			return err
+		}
+
+		// The code proves ownership of the phone, so the number also counts as verified
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"password_hash":       string(hashedPassword),
+			"token_version":       gorm.Expr("token_version + 1"),
+			"is_verified":         true,
+			"must_reset_password": false,
+		}).Error; err != nil {
+			return err
+		}
+		return tokens.RevokeAllSessions(tx, user.ID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired code"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset. Please log in with your new password."})
+}
+
+// respondPasswordResetRequired rejects a login for an account an admin has locked
+// until its password is reset
+func respondPasswordResetRequired(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": "Password reset required. Use forgot password to set a new password.",
+		"code":  "password_reset_required",
+	})
+}
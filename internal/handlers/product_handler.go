@@ -2,10 +2,22 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"farmer-to-buyer-portal/internal/catalog"
+	"farmer-to-buyer-portal/internal/geo"
+	"farmer-to-buyer-portal/internal/inventory"
 	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/moderation"
+	"farmer-to-buyer-portal/internal/money"
+	"farmer-to-buyer-portal/internal/pagination"
+	"farmer-to-buyer-portal/internal/search"
+	"farmer-to-buyer-portal/internal/units"
+	"farmer-to-buyer-portal/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -13,90 +25,192 @@ import (
 
 // CreateProductRequest represents the request payload for creating a product
 type CreateProductRequest struct {
-	CropName     string  `json:"crop_name" binding:"required"`
-	Quantity     float64 `json:"quantity" binding:"required,gt=0"`
-	Unit         string  `json:"unit" binding:"required"`
-	PricePerUnit float64 `json:"price_per_unit" binding:"required,gt=0"`
-	State        string  `json:"state" binding:"required"`
-	City         string  `json:"city" binding:"required"`
-	Pincode      string  `json:"pincode" binding:"required"`
+	CropName     string      `json:"crop_name" binding:"required_without=CropID"` // defaults to the crop's name
+	CropID       string      `json:"crop_id"`                                     // matched from crop_name when empty
+	VarietyID    string      `json:"variety_id"`
+	Description  string      `json:"description" binding:"max=2000"`
+	Quantity     float64     `json:"quantity" binding:"required,gt=0"`
+	Unit         string      `json:"unit" binding:"required"`                // any code or alias in package units
+	PricePerUnit money.Paise `json:"price_per_unit" binding:"required,gt=0"` // rupees, at most two decimals
+	State        string      `json:"state" binding:"required"`
+	City         string      `json:"city" binding:"required"`
+	Pincode      string      `json:"pincode" binding:"required"`
 }
 
 // UpdateProductRequest represents the request payload for updating a product
 type UpdateProductRequest struct {
-	Quantity     *float64 `json:"quantity"`
-	PricePerUnit *float64 `json:"price_per_unit"`
-	Status       *string  `json:"status"`
+	Quantity     *float64     `json:"quantity"`
+	CropID       *string      `json:"crop_id"` // empty string unlinks the crop
+	VarietyID    *string      `json:"variety_id"`
+	Description  *string      `json:"description" binding:"omitempty,max=2000"`
+	Unit         *string      `json:"unit"`
+	PricePerUnit *money.Paise `json:"price_per_unit"`
+	Status       *string      `json:"status"`
 }
 
 // ProductResponse represents the product data in API responses
 type ProductResponse struct {
-	ID           string  `json:"id"`
-	FarmerID     string  `json:"farmer_id"`
-	CropName     string  `json:"crop_name"`
-	Quantity     float64 `json:"quantity"`
-	Unit         string  `json:"unit"`
-	PricePerUnit float64 `json:"price_per_unit"`
-	State        string  `json:"state"`
-	City         string  `json:"city"`
-	Pincode      string  `json:"pincode"`
-	Status       string  `json:"status"`
-	CreatedAt    string  `json:"created_at"`
-	UpdatedAt    string  `json:"updated_at"`
+	ID                string       `json:"id"`
+	FarmerID          string       `json:"farmer_id"`
+	CropName          string       `json:"crop_name"`
+	CropID            *string      `json:"crop_id,omitempty"`
+	VarietyID         *string      `json:"variety_id,omitempty"`
+	Description       string       `json:"description"`
+	Quantity          float64      `json:"quantity"`
+	AvailableQuantity float64      `json:"available_quantity"`
+	ReservedQuantity  float64      `json:"reserved_quantity"`
+	Unit              string       `json:"unit"`
+	PricePerUnit      money.Paise  `json:"price_per_unit"`
+	UnitDimension     string       `json:"unit_dimension,omitempty"`
+	PricePerKg        *money.Paise `json:"price_per_kg,omitempty"`
+	State             string       `json:"state"`
+	City              string       `json:"city"`
+	Pincode           string       `json:"pincode"`
+	DistanceKm        *float64     `json:"distance_km,omitempty"` // from near_pincode, when given
+	Status            string       `json:"status"`
+	CreatedAt         string       `json:"created_at"`
+	UpdatedAt         string       `json:"updated_at"`
+
+	// Only shown to the owner and admins
+	Moderation *ProductModerationResponse `json:"moderation,omitempty"`
+}
+
+// ProductModerationResponse describes an admin moderation decision on a product
+type ProductModerationResponse struct {
+	State       string `json:"state"`
+	Reason      string `json:"reason"`
+	ModeratedAt string `json:"moderated_at,omitempty"`
 }
 
 // toProductResponse converts a Product model to ProductResponse
 func toProductResponse(p models.Product) ProductResponse {
+	var dimension string
+	if u, ok := units.Lookup(p.Unit); ok {
+		dimension = string(u.Dimension)
+	}
+
 	return ProductResponse{
-		ID:           p.ID,
-		FarmerID:     p.FarmerID,
-		CropName:     p.CropName,
-		Quantity:     p.Quantity,
-		Unit:         p.Unit,
-		PricePerUnit: p.PricePerUnit,
-		State:        p.State,
-		City:         p.City,
-		Pincode:      p.Pincode,
-		Status:       p.Status,
-		CreatedAt:    p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:    p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:                p.ID,
+		FarmerID:          p.FarmerID,
+		CropName:          p.CropName,
+		CropID:            p.CropID,
+		VarietyID:         p.VarietyID,
+		Description:       p.Description,
+		Quantity:          p.Quantity,
+		AvailableQuantity: p.Quantity,
+		ReservedQuantity:  p.ReservedQuantity,
+		Unit:              p.Unit,
+		PricePerUnit:      p.PricePerUnit,
+		UnitDimension:     dimension,
+		PricePerKg:        p.PricePerKg,
+		State:             p.State,
+		City:              p.City,
+		Pincode:           p.Pincode,
+		Status:            p.Status,
+		CreatedAt:         p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:         p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
 
-// CreateProduct handles POST /api/v1/products (farmer only)
-func CreateProduct(c *gin.Context) {
-	// Check if user is farmer
-	role := c.MustGet("role").(string)
-	if role != "farmer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only farmers can create products"})
-		return
+// toOwnerProductResponse converts a Product model to ProductResponse including
+// the moderation details only its owner and admins may see
+func toOwnerProductResponse(p models.Product) ProductResponse {
+	response := toProductResponse(p)
+	if p.ModerationState != "" {
+		response.Moderation = &ProductModerationResponse{
+			State:  p.ModerationState,
+			Reason: p.ModerationReason,
+		}
+		if p.ModeratedAt != nil {
+			response.Moderation.ModeratedAt = p.ModeratedAt.Format("2006-01-02T15:04:05Z07:00")
+		}
 	}
+	return response
+}
 
+// CreateProduct handles POST /api/v1/products (farmer only)
+func CreateProduct(c *gin.Context) {
 	var req CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	unit, ok := units.Lookup(req.Unit)
+	if !ok {
+		respondUnknownUnit(c, req.Unit)
+		return
+	}
+	if req.VarietyID != "" && req.CropID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "variety_id requires crop_id"})
+		return
+	}
+
 	db := c.MustGet("db").(*gorm.DB)
 	userID := c.MustGet("user_id").(string)
 
+	// Only farmers with a verified phone number can list products
+	var user models.User
+	if err := db.Select("id", "is_verified").Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !user.IsVerified {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Verify your phone number before listing products"})
+		return
+	}
+
 	product := models.Product{
 		FarmerID:     userID,
-		CropName:     req.CropName,
+		CropName:     catalog.NormalizeTerm(req.CropName),
+		Description:  strings.TrimSpace(req.Description),
 		Quantity:     req.Quantity,
-		Unit:         req.Unit,
+		Unit:         unit.Code,
 		PricePerUnit: req.PricePerUnit,
+		PricePerKg:   units.PricePerKg(req.PricePerUnit, unit.Code),
 		State:        req.State,
 		City:         req.City,
 		Pincode:      req.Pincode,
 		Status:       "active",
 	}
 
+	point, err := geo.Locate(db, product.Pincode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	product.Latitude, product.Longitude = point.LatLng()
+
+	// Link the listing to the crop catalog
+	if req.CropID != "" {
+		crop, variety, err := catalog.FindCrop(db, req.CropID, req.VarietyID)
+		if err != nil {
+			respondCatalogLinkError(c, err)
+			return
+		}
+		product.CropID = &crop.ID
+		if variety != nil {
+			product.VarietyID = &variety.ID
+		}
+		if product.CropName == "" {
+			product.CropName = crop.Name
+		}
+	} else {
+		crop, err := catalog.Resolve(db, product.CropName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if crop != nil {
+			product.CropID = &crop.ID
+		}
+	}
+
 	if err := db.Create(&product).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product"})
 		return
 	}
+	reindexProducts(c, "id = ?", product.ID)
 
 	c.JSON(http.StatusCreated, toProductResponse(product))
 }
@@ -109,7 +223,32 @@ func GetProducts(c *gin.Context) {
 
 	// Apply filters
 	if cropName := c.Query("crop_name"); cropName != "" {
-		query = query.Where("crop_name LIKE ?", "%"+cropName+"%")
+		// Match local names through the catalog as well as the listing's own name
+		cropIDs, err := catalog.MatchCropIDs(db, cropName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+			return
+		}
+		if len(cropIDs) > 0 {
+			query = query.Where(db.Where("crop_name LIKE ?", "%"+cropName+"%").Or("crop_id IN ?", cropIDs))
+		} else {
+			query = query.Where("crop_name LIKE ?", "%"+cropName+"%")
+		}
+	}
+	if cropID := c.Query("crop_id"); cropID != "" {
+		query = query.Where("crop_id = ?", cropID)
+	}
+	if category := c.Query("category"); category != "" {
+		cropIDs, err := catalog.CategoryCropIDs(db, category)
+		if err != nil {
+			if errors.Is(err, catalog.ErrCategoryNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown category " + strconv.Quote(category)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+			return
+		}
+		query = query.Where("crop_id IN ?", cropIDs)
 	}
 	if pincode := c.Query("pincode"); pincode != "" {
 		query = query.Where("pincode = ?", pincode)
@@ -121,40 +260,84 @@ func GetProducts(c *gin.Context) {
 		query = query.Where("state = ?", state)
 	}
 	if minPrice := c.Query("min_price"); minPrice != "" {
-		if min, err := strconv.ParseFloat(minPrice, 64); err == nil {
-			query = query.Where("price_per_unit >= ?", min)
+		if min, err := money.Parse(minPrice); err == nil {
+			query = query.Where("price_per_unit_paise >= ?", min)
 		}
 	}
 	if maxPrice := c.Query("max_price"); maxPrice != "" {
-		if max, err := strconv.ParseFloat(maxPrice, 64); err == nil {
-			query = query.Where("price_per_unit <= ?", max)
+		if max, err := money.Parse(maxPrice); err == nil {
+			query = query.Where("price_per_unit_paise <= ?", max)
 		}
 	}
+	if name := c.Query("unit"); name != "" {
+		unit, ok := units.Lookup(name)
+		if !ok {
+			respondUnknownUnit(c, name)
+			return
+		}
+		query = query.Where("unit = ?", unit.Code)
+	}
 
-	// Sort by created_at desc
-	query = query.Order("created_at DESC")
-
-	var products []models.Product
-	if err := query.Find(&products).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
-		return
+	// Price per kg only exists for listings sold by mass, so filtering or
+	// sorting on it leaves out listings sold by count or volume
+	if minPrice := c.Query("min_price_per_kg"); minPrice != "" {
+		if min, err := money.Parse(minPrice); err == nil {
+			query = query.Where("price_per_kg_paise >= ?", min)
+		}
+	}
+	if maxPrice := c.Query("max_price_per_kg"); maxPrice != "" {
+		if max, err := money.Parse(maxPrice); err == nil {
+			query = query.Where("price_per_kg_paise <= ?", max)
+		}
 	}
 
-	responses := make([]ProductResponse, len(products))
-	for i, p := range products {
-		responses[i] = toProductResponse(p)
+	// Distance from the buyer; listings whose pincode has no known location are left out
+	var origin *geo.Point
+	if pincode := c.Query("near_pincode"); pincode != "" {
+		if err := utils.ValidatePincode(pincode); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "near_pincode must be a 6 digit Indian PIN code"})
+			return
+		}
+		point, err := geo.Locate(db, pincode)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+			return
+		}
+		if point == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No location is known for pincode " + pincode})
+			return
+		}
+		origin = point
+		query = query.Where("latitude IS NOT NULL AND longitude IS NOT NULL")
+
+		if raw := c.Query("radius_km"); raw != "" {
+			radius, err := strconv.ParseFloat(raw, 64)
+			if err != nil || radius <= 0 || radius > geo.MaxRadiusKm {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("radius_km must be a number between 0 and %g", geo.MaxRadiusKm)})
+				return
+			}
+			minLat, maxLat, minLng, maxLng := geo.BoundingBox(*origin, radius)
+			distance, args := geo.DistanceSQL("latitude", "longitude", *origin)
+			query = query.
+				Where("latitude BETWEEN ? AND ?", minLat, maxLat).
+				Where("longitude BETWEEN ? AND ?", minLng, maxLng).
+				Where(distance+" <= ?", append(args, radius)...)
+		}
+	} else if c.Query("radius_km") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "radius_km requires near_pincode"})
+		return
 	}
 
-	c.JSON(http.StatusOK, responses)
+	respondWithProductPage(c, query, toProductResponse, origin)
 }
 
-// GetProduct handles GET /api/v1/products/:id (public)
+// GetProduct handles GET /api/v1/products/:id (public, moderated listings are hidden)
 func GetProduct(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	productID := c.Param("id")
 
 	var product models.Product
-	if err := db.Where("id = ?", productID).First(&product).Error; err != nil {
+	if err := db.Where("id = ? AND status <> ?", productID, moderation.StatusModerated).First(&product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 			return
@@ -168,75 +351,56 @@ func GetProduct(c *gin.Context) {
 
 // GetMyProducts handles GET /api/v1/products/me (farmer only)
 func GetMyProducts(c *gin.Context) {
-	// Check if user is farmer
-	role := c.MustGet("role").(string)
-	if role != "farmer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only farmers can access this endpoint"})
-		return
-	}
-
 	db := c.MustGet("db").(*gorm.DB)
 	userID := c.MustGet("user_id").(string)
 
-	var products []models.Product
-	if err := db.Where("farmer_id = ?", userID).Order("created_at DESC").Find(&products).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
-		return
+	query := db.Model(&models.Product{}).Where("farmer_id = ?", userID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
 	}
 
-	responses := make([]ProductResponse, len(products))
-	for i, p := range products {
-		responses[i] = toProductResponse(p)
-	}
+	respondWithProductPage(c, query, toOwnerProductResponse, nil)
+}
 
-	c.JSON(http.StatusOK, responses)
+// errProductConflict carries a user-facing reason a listing cannot be changed
+type errProductConflict struct {
+	status  int
+	message string
+	reason  string // moderator's reason, if the listing is under moderation
+}
+
+func (e *errProductConflict) Error() string {
+	return e.message
 }
 
 // UpdateProduct handles PUT /api/v1/products/:id (farmer only, owner only)
 func UpdateProduct(c *gin.Context) {
-	// Check if user is farmer
-	role := c.MustGet("role").(string)
-	if role != "farmer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only farmers can update products"})
-		return
-	}
-
 	db := c.MustGet("db").(*gorm.DB)
 	productID := c.Param("id")
 	userID := c.MustGet("user_id").(string)
 
-	// Check if product exists and belongs to the user
-	var product models.Product
-	if err := db.Where("id = ? AND farmer_id = ?", productID, userID).First(&product).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found or you don't have permission to update it"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-		return
-	}
-
 	var req UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update only provided fields
-	updates := make(map[string]interface{})
-	if req.Quantity != nil {
-		if *req.Quantity <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity must be greater than 0"})
-			return
-		}
-		updates["quantity"] = *req.Quantity
+	// Validate what does not depend on the stored listing
+	var unit units.Unit
+	if req.Quantity != nil && *req.Quantity <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity must be greater than 0"})
+		return
 	}
-	if req.PricePerUnit != nil {
-		if *req.PricePerUnit <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Price per unit must be greater than 0"})
+	if req.Unit != nil {
+		var ok bool
+		if unit, ok = units.Lookup(*req.Unit); !ok {
+			respondUnknownUnit(c, *req.Unit)
 			return
 		}
-		updates["price_per_unit"] = *req.PricePerUnit
+	}
+	if req.PricePerUnit != nil && *req.PricePerUnit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Price per unit must be greater than 0"})
+		return
 	}
 	if req.Status != nil {
 		validStatuses := []string{"active", "closed", "sold"}
@@ -251,52 +415,328 @@ func UpdateProduct(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of: active, closed, sold"})
 			return
 		}
-		updates["status"] = *req.Status
 	}
 
-	if len(updates) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
-		return
-	}
+	var product models.Product
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Lock the listing so a takedown or a stock reservation cannot land
+		// between the checks below and the update
+		locked, err := inventory.LockProducts(tx, []string{productID})
+		if err != nil {
+			return err
+		}
+		product = *locked[productID]
+		if product.FarmerID != userID {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Removed listings are locked; hidden ones can be fixed but not re-listed by the farmer
+		if product.ModerationState == moderation.StateRemoved {
+			return &errProductConflict{status: http.StatusForbidden, message: "This listing was removed by moderators and can no longer be edited", reason: product.ModerationReason}
+		}
+		if req.Status != nil && product.Status == moderation.StatusModerated {
+			return &errProductConflict{status: http.StatusForbidden, message: "This listing is hidden by moderators; its status can only be changed by an admin", reason: product.ModerationReason}
+		}
+
+		// Update only provided fields
+		updates := make(map[string]interface{})
+		if req.CropID != nil || req.VarietyID != nil {
+			cropID := ""
+			if product.CropID != nil {
+				cropID = *product.CropID
+			}
+			if req.CropID != nil {
+				cropID = *req.CropID
+			}
+			varietyID := ""
+			if req.VarietyID != nil {
+				varietyID = *req.VarietyID
+			} else if req.CropID == nil && product.VarietyID != nil {
+				varietyID = *product.VarietyID
+			}
 
-	if err := db.Model(&product).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
+			switch {
+			case cropID == "" && varietyID != "":
+				return &errProductConflict{status: http.StatusBadRequest, message: "variety_id requires crop_id"}
+			case cropID == "":
+				updates["crop_id"] = nil
+				updates["variety_id"] = nil
+			default:
+				crop, variety, err := catalog.FindCrop(tx, cropID, varietyID)
+				if err != nil {
+					return err
+				}
+				updates["crop_id"] = crop.ID
+				updates["variety_id"] = nil
+				if variety != nil {
+					updates["variety_id"] = variety.ID
+				}
+			}
+		}
+		if req.Description != nil {
+			updates["description"] = strings.TrimSpace(*req.Description)
+		}
+		if req.Quantity != nil {
+			updates["quantity"] = *req.Quantity
+		}
+		if req.Unit != nil {
+			// Reserved stock was ordered in the old unit. The row lock keeps a
+			// reservation from committing between this check and the update.
+			if unit.Code != product.Unit && product.ReservedQuantity > 0 {
+				return &errProductConflict{status: http.StatusConflict, message: "The unit cannot be changed while orders hold stock of this product"}
+			}
+			updates["unit"] = unit.Code
+			product.Unit = unit.Code
+		}
+		if req.PricePerUnit != nil {
+			updates["price_per_unit_paise"] = *req.PricePerUnit
+			product.PricePerUnit = *req.PricePerUnit
+		}
+		if req.Unit != nil || req.PricePerUnit != nil {
+			updates["price_per_kg_paise"] = units.PricePerKg(product.PricePerUnit, product.Unit)
+		}
+		if req.Status != nil {
+			updates["status"] = *req.Status
+		}
+
+		if len(updates) == 0 {
+			return &errProductConflict{status: http.StatusBadRequest, message: "No fields to update"}
+		}
+		return tx.Model(&product).Updates(updates).Error
+	})
+	if err != nil {
+		respondProductError(c, err, "Product not found or you don't have permission to update it", "Failed to update product")
 		return
 	}
+	reindexProducts(c, "id = ?", productID)
 
 	// Reload product to get updated values
 	db.Where("id = ?", productID).First(&product)
-	c.JSON(http.StatusOK, toProductResponse(product))
+	c.JSON(http.StatusOK, toOwnerProductResponse(product))
 }
 
-// DeleteProduct handles DELETE /api/v1/products/:id (farmer only, owner only)
-func DeleteProduct(c *gin.Context) {
-	// Check if user is farmer
-	role := c.MustGet("role").(string)
-	if role != "farmer" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only farmers can delete products"})
-		return
+// respondProductError maps a failed change to a farmer's own listing to an HTTP response
+func respondProductError(c *gin.Context, err error, notFound, fallback string) {
+	var conflict *errProductConflict
+	switch {
+	case errors.Is(err, inventory.ErrProductNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.As(err, &conflict):
+		body := gin.H{"error": conflict.message}
+		if conflict.reason != "" {
+			body["reason"] = conflict.reason
+		}
+		c.JSON(conflict.status, body)
+	case errors.Is(err, catalog.ErrCropNotFound), errors.Is(err, catalog.ErrVarietyNotFound):
+		respondCatalogLinkError(c, err)
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
 	}
+}
 
+// DeleteProduct handles DELETE /api/v1/products/:id (farmer only, owner only).
+// Listings holding stock for pending orders cannot be deleted; deleting them
+// would drop the reservations along with the row.
+func DeleteProduct(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	productID := c.Param("id")
 	userID := c.MustGet("user_id").(string)
 
-	// Check if product exists and belongs to the user
-	var product models.Product
-	if err := db.Where("id = ? AND farmer_id = ?", productID, userID).First(&product).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found or you don't have permission to delete it"})
+	err := db.Transaction(func(tx *gorm.DB) error {
+		locked, err := inventory.LockProducts(tx, []string{productID})
+		if err != nil {
+			return err
+		}
+		product := locked[productID]
+		if product.FarmerID != userID {
+			return gorm.ErrRecordNotFound
+		}
+		if product.ReservedQuantity > 0 {
+			return &errProductConflict{status: http.StatusConflict, message: "This product holds stock for pending orders; accept or reject them before deleting it"}
+		}
+		return tx.Delete(product).Error
+	})
+	if err != nil {
+		respondProductError(c, err, "Product not found or you don't have permission to delete it", "Failed to delete product")
+		return
+	}
+	searcher := c.MustGet("search").(search.Searcher)
+	if err := searcher.Remove(c.Request.Context(), productID); err != nil {
+		log.Printf("WARNING: failed to remove product %s from search: %v", productID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
+}
+
+// UnitResponse represents a unit of measure in API responses
+type UnitResponse struct {
+	Code      string   `json:"code"`
+	Name      string   `json:"name"`
+	Dimension string   `json:"dimension"`
+	BaseUnits float64  `json:"base_units"` // how many kg, pieces or litres one unit is
+	Aliases   []string `json:"aliases"`
+}
+
+// toUnitResponse converts a units.Unit to UnitResponse
+func toUnitResponse(u units.Unit) UnitResponse {
+	return UnitResponse{
+		Code:      u.Code,
+		Name:      u.Name,
+		Dimension: string(u.Dimension),
+		BaseUnits: float64(u.Num) / float64(u.Den),
+		Aliases:   u.Aliases,
+	}
+}
+
+// GetUnits handles GET /api/v1/units (public).
+// Lists the units products can be listed in and the names they accept.
+func GetUnits(c *gin.Context) {
+	all := units.All()
+	responses := make([]UnitResponse, len(all))
+	for i, u := range all {
+		responses[i] = toUnitResponse(u)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// productSorts are the orderings product listings accept; the first is the default
+var productSorts = []pagination.Sort{
+	{Name: "newest", Column: "created_at", Kind: pagination.KindTime, Desc: true},
+	{Name: "price_asc", Column: "price_per_unit_paise", Kind: pagination.KindInt},
+	{Name: "price_desc", Column: "price_per_unit_paise", Kind: pagination.KindInt, Desc: true},
+	{Name: "price_per_kg_asc", Column: "price_per_kg_paise", Kind: pagination.KindInt},
+	{Name: "price_per_kg_desc", Column: "price_per_kg_paise", Kind: pagination.KindInt, Desc: true},
+	{Name: "quantity_asc", Column: "quantity", Kind: pagination.KindFloat},
+	{Name: "quantity_desc", Column: "quantity", Kind: pagination.KindFloat, Desc: true},
+}
+
+// distanceSort orders products nearest to origin first
+func distanceSort(origin geo.Point) pagination.Sort {
+	distance, args := geo.DistanceSQL("latitude", "longitude", origin)
+	return pagination.Sort{Name: "distance", Column: distance, Args: args, Kind: pagination.KindFloat}
+}
+
+// productRow is a product as fetched for a listing, with its distance from
+// near_pincode when one was given
+type productRow struct {
+	models.Product
+	DistanceKm *float64
+}
+
+// productSortKey returns the value of row that sort orders by
+func productSortKey(sort pagination.Sort, row productRow) interface{} {
+	p := row.Product
+	if sort.Name == "distance" {
+		return *row.DistanceKm
+	}
+	switch sort.Column {
+	case "price_per_unit_paise":
+		return int64(p.PricePerUnit)
+	case "price_per_kg_paise":
+		return int64(*p.PricePerKg)
+	case "quantity":
+		return p.Quantity
+	default:
+		return p.CreatedAt
+	}
+}
+
+// ProductListResponse is one page of products
+type ProductListResponse struct {
+	Items []ProductResponse `json:"items"`
+	Page  pagination.Page   `json:"page"`
+}
+
+// respondWithProductPage pages through the products matched by query using
+// the limit, cursor and sort parameters and writes the page as the response.
+// Without paging parameters it writes every product as a bare array.
+// With an origin, every product has coordinates and gets its distance from
+// origin, and the page is nearest first unless another sort is asked for.
+func respondWithProductPage(c *gin.Context, query *gorm.DB, toResponse func(models.Product) ProductResponse, origin *geo.Point) {
+	paged := pagination.Requested(c.Query("limit"), c.Query("cursor"), c.Query("sort"))
+	writeProductPage(c, query, toResponse, origin, paged)
+}
+
+// writeProductPage is respondWithProductPage with the choice between a page and
+// a bare array made by the caller
+func writeProductPage(c *gin.Context, query *gorm.DB, toResponse func(models.Product) ProductResponse, origin *geo.Point, paged bool) {
+	sorts := productSorts
+	if origin != nil {
+		sorts = append([]pagination.Sort{distanceSort(*origin)}, productSorts...)
+	} else if c.Query("sort") == "distance" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort=distance requires near_pincode"})
+		return
+	}
+
+	req, err := pagination.Parse(c.Query("limit"), c.Query("cursor"), c.Query("sort"), sorts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Sort.Column == "price_per_kg_paise" {
+		query = query.Where("price_per_kg_paise IS NOT NULL")
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if paged {
+		if err := query.Count(&total).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	}
+
+	if origin != nil {
+		distance, args := geo.DistanceSQL("latitude", "longitude", *origin)
+		query = query.Select("products.*, "+distance+" AS distance_km", args...)
+	}
+	if paged {
+		query = req.Apply(query)
+	} else {
+		query = req.Order(query)
+	}
+
+	var rows []productRow
+	if err := query.Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
 		return
 	}
 
-	if err := db.Delete(&product).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product"})
+	n := len(rows)
+	var page pagination.Page
+	if paged {
+		page, n = req.Page(len(rows), total, func(i int) (interface{}, string) {
+			return productSortKey(req.Sort, rows[i]), rows[i].ID
+		})
+	}
+	responses := make([]ProductResponse, n)
+	for i, row := range rows[:n] {
+		responses[i] = toResponse(row.Product)
+		responses[i].DistanceKm = row.DistanceKm
+	}
+
+	if !paged {
+		c.JSON(http.StatusOK, responses)
 		return
 	}
+	c.JSON(http.StatusOK, ProductListResponse{Items: responses, Page: page})
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
+// respondCatalogLinkError maps a failure to link a listing to the crop catalog to an HTTP response
+func respondCatalogLinkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, catalog.ErrCropNotFound), errors.Is(err, catalog.ErrVarietyNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	}
+}
+
+// respondUnknownUnit rejects a unit that is not in the catalog
+func respondUnknownUnit(c *gin.Context, name string) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":       "Unknown unit " + strconv.Quote(name),
+		"valid_units": units.Codes(),
+	})
 }
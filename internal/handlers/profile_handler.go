@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/geo"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FarmerProfileRequest represents the request payload for creating or updating a farmer profile
+type FarmerProfileRequest struct {
+	FarmName      string  `json:"farm_name" binding:"required,max=255"`
+	State         string  `json:"state" binding:"required,max=100"`
+	City          string  `json:"city" binding:"required,max=100"`
+	Pincode       string  `json:"pincode" binding:"required"`
+	Address       string  `json:"address"`
+	FarmSizeAcres float64 `json:"farm_size_acres" binding:"gte=0"`
+}
+
+// BuyerProfileRequest represents the request payload for creating or updating a buyer profile
+type BuyerProfileRequest struct {
+	BuyerType    string `json:"buyer_type" binding:"required,oneof=individual restaurant vendor"`
+	BusinessName string `json:"business_name" binding:"max=255"`
+	GSTNumber    string `json:"gst_number"`
+	State        string `json:"state" binding:"required,max=100"`
+	City         string `json:"city" binding:"required,max=100"`
+	Pincode      string `json:"pincode" binding:"required"`
+	Address      string `json:"address"`
+}
+
+// FarmerProfileResponse represents the farmer profile data in API responses
+type FarmerProfileResponse struct {
+	FarmerID      string   `json:"farmer_id"`
+	FarmName      string   `json:"farm_name"`
+	State         string   `json:"state"`
+	City          string   `json:"city"`
+	Pincode       string   `json:"pincode"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	Address       string   `json:"address"`
+	FarmSizeAcres float64  `json:"farm_size_acres"`
+	Rating        float64  `json:"rating"`
+	TotalOrders   int      `json:"total_orders"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+}
+
+// BuyerProfileResponse represents the buyer profile data in API responses
+type BuyerProfileResponse struct {
+	BuyerID      string   `json:"buyer_id"`
+	BuyerType    string   `json:"buyer_type"`
+	BusinessName string   `json:"business_name"`
+	GSTNumber    string   `json:"gst_number"`
+	State        string   `json:"state"`
+	City         string   `json:"city"`
+	Pincode      string   `json:"pincode"`
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	Address      string   `json:"address"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+// toFarmerProfileResponse converts a FarmerProfile model to FarmerProfileResponse
+func toFarmerProfileResponse(p models.FarmerProfile) FarmerProfileResponse {
+	return FarmerProfileResponse{
+		FarmerID:      p.FarmerID,
+		FarmName:      p.FarmName,
+		State:         p.State,
+		City:          p.City,
+		Pincode:       p.Pincode,
+		Latitude:      p.Latitude,
+		Longitude:     p.Longitude,
+		Address:       p.Address,
+		FarmSizeAcres: p.FarmSizeAcres,
+		Rating:        p.Rating,
+		TotalOrders:   p.TotalOrders,
+		CreatedAt:     p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// toBuyerProfileResponse converts a BuyerProfile model to BuyerProfileResponse
+func toBuyerProfileResponse(p models.BuyerProfile) BuyerProfileResponse {
+	return BuyerProfileResponse{
+		BuyerID:      p.BuyerID,
+		BuyerType:    p.BuyerType,
+		BusinessName: p.BusinessName,
+		GSTNumber:    p.GSTNumber,
+		State:        p.State,
+		City:         p.City,
+		Pincode:      p.Pincode,
+		Latitude:     p.Latitude,
+		Longitude:    p.Longitude,
+		Address:      p.Address,
+		CreatedAt:    p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:    p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// validate checks fields that binding tags cannot express
+func (r *FarmerProfileRequest) validate() error {
+	return utils.ValidatePincode(r.Pincode)
+}
+
+// validate checks fields that binding tags cannot express and normalizes the GSTIN
+func (r *BuyerProfileRequest) validate() error {
+	if err := utils.ValidatePincode(r.Pincode); err != nil {
+		return err
+	}
+	if r.GSTNumber != "" {
+		gstin, err := utils.NormalizeGSTIN(r.GSTNumber)
+		if err != nil {
+			return err
+		}
+		r.GSTNumber = gstin
+	}
+	return nil
+}
+
+// farmerProfileColumns are the columns a farmer may edit. Rating and order
+// count are maintained by reviews and deliveries and are never written back.
+var farmerProfileColumns = []string{"farm_name", "state", "city", "pincode", "address", "farm_size_acres", "latitude", "longitude"}
+
+// buyerProfileColumns are the columns a buyer may edit
+var buyerProfileColumns = []string{"buyer_type", "business_name", "gst_number", "state", "city", "pincode", "address", "latitude", "longitude"}
+
+// apply copies the request fields onto a FarmerProfile model
+func (r *FarmerProfileRequest) apply(p *models.FarmerProfile) {
+	p.FarmName = r.FarmName
+	p.State = r.State
+	p.City = r.City
+	p.Pincode = r.Pincode
+	p.Address = r.Address
+	p.FarmSizeAcres = r.FarmSizeAcres
+}
+
+// apply copies the request fields onto a BuyerProfile model
+func (r *BuyerProfileRequest) apply(p *models.BuyerProfile) {
+	p.BuyerType = r.BuyerType
+	p.BusinessName = r.BusinessName
+	p.GSTNumber = r.GSTNumber
+	p.State = r.State
+	p.City = r.City
+	p.Pincode = r.Pincode
+	p.Address = r.Address
+}
+
+// GetFarmerProfile handles GET /api/v1/profile/farmer (farmer only)
+func GetFarmerProfile(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+
+	var profile models.FarmerProfile
+	if err := db.Where("farmer_id = ?", userID).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Farmer profile not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toFarmerProfileResponse(profile))
+}
+
+// UpdateFarmerProfile handles PUT /api/v1/profile/farmer (farmer only, creates the profile if missing)
+func UpdateFarmerProfile(c *gin.Context) {
+	var req FarmerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+
+	point, err := geo.Locate(db, req.Pincode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var profile models.FarmerProfile
+	err = db.Where("farmer_id = ?", userID).First(&profile).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	req.apply(&profile)
+	profile.Latitude, profile.Longitude = point.LatLng()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		profile.FarmerID = userID
+		err = db.Create(&profile).Error
+	} else {
+		err = db.Model(&profile).Select(farmerProfileColumns).Updates(&profile).Error
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// Another request created the profile first
+		c.JSON(http.StatusConflict, gin.H{"error": "Farmer profile already exists, retry the update"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save farmer profile"})
+		return
+	}
+	// Listings are searchable by farm name
+	reindexProducts(c, "farmer_id = ?", userID)
+
+	c.JSON(http.StatusOK, toFarmerProfileResponse(profile))
+}
+
+// GetBuyerProfile handles GET /api/v1/profile/buyer (buyer only)
+func GetBuyerProfile(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+
+	var profile models.BuyerProfile
+	if err := db.Where("buyer_id = ?", userID).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Buyer profile not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toBuyerProfileResponse(profile))
+}
+
+// UpdateBuyerProfile handles PUT /api/v1/profile/buyer (buyer only, creates the profile if missing)
+func UpdateBuyerProfile(c *gin.Context) {
+	var req BuyerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+
+	point, err := geo.Locate(db, req.Pincode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var profile models.BuyerProfile
+	err = db.Where("buyer_id = ?", userID).First(&profile).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	req.apply(&profile)
+	profile.Latitude, profile.Longitude = point.LatLng()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		profile.BuyerID = userID
+		err = db.Create(&profile).Error
+	} else {
+		err = db.Model(&profile).Select(buyerProfileColumns).Updates(&profile).Error
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// Another request created the profile first
+		c.JSON(http.StatusConflict, gin.H{"error": "Buyer profile already exists, retry the update"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save buyer profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toBuyerProfileResponse(profile))
+}
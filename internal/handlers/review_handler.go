@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/ratings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateReviewRequest represents the request payload for reviewing a delivered order
+type CreateReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+// ReviewReplyRequest represents the request payload for a farmer's public reply
+type ReviewReplyRequest struct {
+	Reply string `json:"reply" binding:"required,max=2000"`
+}
+
+// ReviewResponse represents a review in API responses
+type ReviewResponse struct {
+	ID        string `json:"id"`
+	OrderID   string `json:"order_id"`
+	FarmerID  string `json:"farmer_id"`
+	BuyerName string `json:"buyer_name"`
+	Rating    int    `json:"rating"`
+	Comment   string `json:"comment"`
+	Reply     string `json:"reply,omitempty"`
+	RepliedAt string `json:"replied_at,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// errReviewConflict carries a user-facing reason a review action is not allowed
+type errReviewConflict struct {
+	status  int
+	message string
+}
+
+func (e *errReviewConflict) Error() string {
+	return e.message
+}
+
+// toReviewResponse converts a Review model (with Buyer loaded) to ReviewResponse
+func toReviewResponse(r models.Review) ReviewResponse {
+	response := ReviewResponse{
+		ID:        r.ID,
+		OrderID:   r.OrderID,
+		FarmerID:  r.FarmerID,
+		BuyerName: r.Buyer.Name,
+		Rating:    r.Rating,
+		Comment:   r.Comment,
+		Reply:     r.Reply,
+		CreatedAt: r.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if r.RepliedAt != nil {
+		response.RepliedAt = r.RepliedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// CreateReview handles POST /api/v1/orders/:id/review (buyer only, delivered orders only)
+func CreateReview(c *gin.Context) {
+	var req CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	orderID := c.Param("id")
+	buyerID := c.MustGet("user_id").(string)
+
+	var review models.Review
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Only the buyer's own order can be reviewed
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND buyer_id = ?", orderID, buyerID).
+			First(&order).Error; err != nil {
+			return err
+		}
+
+		// Lock the farmer's profile before reading any aggregates so concurrent
+		// reviews for the same farmer are applied one after another
+		var profile models.FarmerProfile
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("farmer_id = ?", order.FarmerID).
+			Limit(1).
+			Find(&profile).Error; err != nil {
+			return err
+		}
+
+		if order.Status != "delivered" {
+			return &errReviewConflict{status: http.StatusBadRequest, message: "Only delivered orders can be reviewed"}
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Review{}).Where("order_id = ?", order.ID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return &errReviewConflict{status: http.StatusConflict, message: "This order has already been reviewed"}
+		}
+
+		review = models.Review{
+			OrderID:  order.ID,
+			FarmerID: order.FarmerID,
+			BuyerID:  buyerID,
+			Rating:   req.Rating,
+			Comment:  req.Comment,
+		}
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		return ratings.RefreshFarmerStats(tx, order.FarmerID)
+	})
+	if err != nil {
+		respondReviewError(c, err, "Failed to create review")
+		return
+	}
+
+	respondWithReview(c, http.StatusCreated, review.ID)
+}
+
+// ReplyToReview handles POST /api/v1/reviews/:id/reply (farmer only, one reply per review)
+func ReplyToReview(c *gin.Context) {
+	var req ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	reviewID := c.Param("id")
+	farmerID := c.MustGet("user_id").(string)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND farmer_id = ?", reviewID, farmerID).
+			First(&review).Error; err != nil {
+			return err
+		}
+		if review.RepliedAt != nil {
+			return &errReviewConflict{status: http.StatusConflict, message: "This review already has a reply"}
+		}
+
+		now := time.Now()
+		return tx.Model(&review).Updates(map[string]interface{}{
+			"reply":      req.Reply,
+			"replied_at": now,
+		}).Error
+	})
+	if err != nil {
+		respondReviewError(c, err, "Failed to reply to review")
+		return
+	}
+
+	respondWithReview(c, http.StatusOK, reviewID)
+}
+
+// GetFarmerReviews handles GET /api/v1/farmers/:id/reviews (public)
+func GetFarmerReviews(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	farmerID := c.Param("id")
+
+	var reviews []models.Review
+	if err := db.Preload("Buyer").
+		Where("farmer_id = ?", farmerID).
+		Order("created_at DESC").
+		Find(&reviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		return
+	}
+
+	respondWithReviews(c, reviews)
+}
+
+// GetProductReviews handles GET /api/v1/products/:id/reviews (public)
+func GetProductReviews(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	productID := c.Param("id")
+
+	var reviews []models.Review
+	if err := db.Preload("Buyer").
+		Where("order_id IN (?)", db.Model(&models.OrderItem{}).Select("order_id").Where("product_id = ?", productID)).
+		Order("created_at DESC").
+		Find(&reviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		return
+	}
+
+	respondWithReviews(c, reviews)
+}
+
+// respondWithReviews writes a list of reviews as the response
+func respondWithReviews(c *gin.Context, reviews []models.Review) {
+	responses := make([]ReviewResponse, len(reviews))
+	for i, r := range reviews {
+		responses[i] = toReviewResponse(r)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// respondWithReview reloads a review with its buyer and writes it as the response
+func respondWithReview(c *gin.Context, status int, reviewID string) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	var review models.Review
+	if err := db.Preload("Buyer").Where("id = ?", reviewID).First(&review).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review"})
+		return
+	}
+
+	c.JSON(status, toReviewResponse(review))
+}
+
+// respondReviewError maps a review failure to an HTTP response
+func respondReviewError(c *gin.Context, err error, fallback string) {
+	var conflict *errReviewConflict
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found or you don't have permission to access it"})
+	case errors.As(err, &conflict):
+		c.JSON(conflict.status, gin.H{"error": conflict.message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
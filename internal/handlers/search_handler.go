@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/pagination"
+	"farmer-to-buyer-portal/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SearchResultResponse is a product in search results with its relevance score
+type SearchResultResponse struct {
+	ProductResponse
+	Score float64 `json:"score"`
+}
+
+// SearchResponse represents one page of product search results
+type SearchResponse struct {
+	Query          string                 `json:"query"`
+	CorrectedQuery string                 `json:"corrected_query,omitempty"`
+	Total          int                    `json:"total"`
+	Limit          int                    `json:"limit"`
+	Offset         int                    `json:"offset"`
+	Items          []SearchResultResponse `json:"items"`
+	Facets         search.Facets          `json:"facets"`
+}
+
+// SearchProducts handles GET /api/v1/products/search (public).
+// Ranks active listings by relevance to q, tolerating typos, and counts the
+// matches per state, category and price band. Narrow with state, category
+// (slug) and price_band; page with limit and offset.
+func SearchProducts(c *gin.Context) {
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	limit := pagination.DefaultLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > pagination.MaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": pagination.ErrInvalidLimit.Error()})
+			return
+		}
+		limit = n
+	}
+	offset := 0
+	if raw := c.Query("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+			return
+		}
+		offset = n
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	searcher := c.MustGet("search").(search.Searcher)
+
+	result, err := searcher.Search(c.Request.Context(), search.Query{
+		Text:      text,
+		State:     c.Query("state"),
+		Category:  c.Query("category"),
+		PriceBand: c.Query("price_band"),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		if errors.Is(err, search.ErrUnknownPriceBand) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown price_band " + strconv.Quote(c.Query("price_band"))})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search products"})
+		return
+	}
+
+	// Load the listings in rank order, dropping any that changed since they were indexed
+	ids := make([]string, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ProductID
+	}
+	var products []models.Product
+	if len(ids) > 0 {
+		if err := db.Where("id IN ? AND status = ?", ids, "active").Find(&products).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search products"})
+			return
+		}
+	}
+	byID := make(map[string]models.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	items := make([]SearchResultResponse, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		if p, ok := byID[hit.ProductID]; ok {
+			items = append(items, SearchResultResponse{ProductResponse: toProductResponse(p), Score: hit.Score})
+		}
+	}
+
+	c.JSON(http.StatusOK, SearchResponse{
+		Query:          text,
+		CorrectedQuery: result.CorrectedText,
+		Total:          result.Total,
+		Limit:          limit,
+		Offset:         offset,
+		Items:          items,
+		Facets:         result.Facets,
+	})
+}
+
+// reindexProducts refreshes the search documents of the products matching
+// query. Call it after the change has committed; failures are only logged
+// because the change itself has been saved.
+func reindexProducts(c *gin.Context, query interface{}, args ...interface{}) {
+	db := c.MustGet("db").(*gorm.DB)
+	searcher := c.MustGet("search").(search.Searcher)
+	if err := search.Reindex(c.Request.Context(), db, searcher, append([]interface{}{query}, args...)...); err != nil {
+		log.Printf("WARNING: failed to reindex products for search: %v", err)
+	}
+}
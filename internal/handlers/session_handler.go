@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/tokens"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SessionResponse represents a login session in API responses
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	LastSeenAt string `json:"last_seen_at"`
+	CreatedAt  string `json:"created_at"`
+	Current    bool   `json:"current"`
+}
+
+// toSessionResponse converts a Session model to SessionResponse
+func toSessionResponse(s models.Session, currentSessionID string) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		LastSeenAt: s.LastSeenAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:  s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Current:    s.ID == currentSessionID,
+	}
+}
+
+// GetSessions handles GET /api/v1/auth/sessions
+func GetSessions(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+	sessionID := c.MustGet("session_id").(string)
+
+	sessions, err := tokens.ActiveSessions(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	responses := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		responses[i] = toSessionResponse(s, sessionID)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RevokeSession handles DELETE /api/v1/auth/sessions/:id
+func RevokeSession(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+
+	if err := tokens.RevokeSession(db, userID, c.Param("id")); err != nil {
+		if errors.Is(err, tokens.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+// RevokeAllSessions handles DELETE /api/v1/auth/sessions, logging out everywhere
+func RevokeAllSessions(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	userID := c.MustGet("user_id").(string)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return tokens.RevokeAllSessions(tx, userID)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions revoked successfully"})
+}
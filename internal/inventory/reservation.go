@@ -0,0 +1,205 @@
+package inventory
+
+import (
+	"errors"
+	"time"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reservation statuses
+const (
+	ReservationHeld      = "held"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// ErrReservationExpired is returned when committing an order whose hold has lapsed
+var ErrReservationExpired = errors.New("stock reservation has expired")
+
+var reservationTTL = 24 * time.Hour
+
+// Init configures the reservation hold window from config
+func Init(cfg config.Config) {
+	if cfg.ReservationTTL > 0 {
+		reservationTTL = cfg.ReservationTTL
+	}
+}
+
+// Reserve moves quantity of a locked product from available to reserved stock and
+// records a hold against orderID. The product is marked sold once nothing is left.
+func Reserve(tx *gorm.DB, product *models.Product, orderID string, quantity float64) error {
+	if err := CheckAvailable(product, quantity); err != nil {
+		return err
+	}
+
+	available := roundQuantity(product.Quantity - quantity)
+	if available < 0 {
+		available = 0
+	}
+	reserved := roundQuantity(product.ReservedQuantity + quantity)
+	updates := map[string]interface{}{
+		"quantity":          available,
+		"reserved_quantity": reserved,
+	}
+	if available == 0 {
+		updates["status"] = "sold"
+	}
+	if err := tx.Model(product).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	product.Quantity = available
+	product.ReservedQuantity = reserved
+	if available == 0 {
+		product.Status = "sold"
+	}
+
+	reservation := models.StockReservation{
+		OrderID:   orderID,
+		ProductID: product.ID,
+		Quantity:  quantity,
+		Status:    ReservationHeld,
+		ExpiresAt: time.Now().Add(reservationTTL),
+	}
+	return tx.Create(&reservation).Error
+}
+
+// Commit turns the held reservations of an order into sold stock
+func Commit(tx *gorm.DB, orderID string) error {
+	reservations, products, err := lockReservations(tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, r := range reservations {
+		if r.Status == ReservationExpired || (r.Status == ReservationHeld && now.After(r.ExpiresAt)) {
+			return ErrReservationExpired
+		}
+	}
+
+	for _, r := range reservations {
+		if r.Status != ReservationHeld {
+			continue
+		}
+		product := products[r.ProductID]
+		reserved := roundQuantity(product.ReservedQuantity - r.Quantity)
+		if reserved < 0 {
+			reserved = 0
+		}
+		if err := tx.Model(product).Update("reserved_quantity", reserved).Error; err != nil {
+			return err
+		}
+		product.ReservedQuantity = reserved
+
+		if err := tx.Model(&r).Update("status", ReservationCommitted).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release returns the held stock of an order to available quantity, marking the
+// holds with status (ReservationReleased or ReservationExpired). Products that
+// were sold out by the hold become active again.
+func Release(tx *gorm.DB, orderID, status string) error {
+	reservations, products, err := lockReservations(tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range reservations {
+		if r.Status != ReservationHeld {
+			continue
+		}
+		product := products[r.ProductID]
+		if err := Restock(tx, product, r.Quantity); err != nil {
+			return err
+		}
+		reserved := roundQuantity(product.ReservedQuantity - r.Quantity)
+		if reserved < 0 {
+			reserved = 0
+		}
+		if err := tx.Model(product).Update("reserved_quantity", reserved).Error; err != nil {
+			return err
+		}
+		product.ReservedQuantity = reserved
+
+		if err := tx.Model(&r).Update("status", status).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restock adds quantity back to the available stock of a locked product
+func Restock(tx *gorm.DB, product *models.Product, quantity float64) error {
+	available := roundQuantity(product.Quantity + quantity)
+	updates := map[string]interface{}{"quantity": available}
+	if product.Status == "sold" && available > 0 {
+		updates["status"] = "active"
+	}
+	if err := tx.Model(product).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	product.Quantity = available
+	if status, ok := updates["status"].(string); ok {
+		product.Status = status
+	}
+	return nil
+}
+
+// lockReservations loads an order's reservations and then their products FOR
+// UPDATE, each in ID order. Callers lock the order row first, so every path
+// that changes an order's stock takes its locks in the same order.
+func lockReservations(tx *gorm.DB, orderID string) ([]models.StockReservation, map[string]*models.Product, error) {
+	var reservations []models.StockReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		Order("id ASC").
+		Find(&reservations).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(reservations) == 0 {
+		return nil, map[string]*models.Product{}, nil
+	}
+
+	productIDs := make([]string, 0, len(reservations))
+	for _, r := range reservations {
+		productIDs = append(productIDs, r.ProductID)
+	}
+	products, err := LockProducts(tx, productIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reservations, products, nil
+}
+
+// ReturnCommitted puts the stock sold to an accepted order back on its listings,
+// for orders cancelled after acceptance
+func ReturnCommitted(tx *gorm.DB, orderID string) error {
+	reservations, products, err := lockReservations(tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range reservations {
+		if r.Status != ReservationCommitted {
+			continue
+		}
+		if err := Restock(tx, products[r.ProductID], r.Quantity); err != nil {
+			return err
+		}
+		if err := tx.Model(&r).Update("status", ReservationReleased).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
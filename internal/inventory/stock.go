@@ -0,0 +1,92 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrProductNotFound is returned when a locked product does not exist
+var ErrProductNotFound = errors.New("product not found")
+
+// ErrProductUnavailable is returned when a product is not open for ordering
+var ErrProductUnavailable = errors.New("product is not available for ordering")
+
+// InsufficientStockError reports that a product cannot cover the requested quantity
+type InsufficientStockError struct {
+	ProductID string
+	CropName  string
+	Requested float64
+	Available float64
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for %s: requested %.2f, available %.2f", e.CropName, e.Requested, e.Available)
+}
+
+// ProductError ties ErrProductNotFound/ErrProductUnavailable to a product ID
+type ProductError struct {
+	ProductID string
+	Err       error
+}
+
+func (e *ProductError) Error() string {
+	return fmt.Sprintf("%s: %v", e.ProductID, e.Err)
+}
+
+func (e *ProductError) Unwrap() error {
+	return e.Err
+}
+
+// LockProducts loads the given products with SELECT ... FOR UPDATE inside tx.
+// Rows are locked in ID order so concurrent checkouts cannot deadlock.
+func LockProducts(tx *gorm.DB, productIDs []string) (map[string]*models.Product, error) {
+	ids := append([]string(nil), productIDs...)
+	sort.Strings(ids)
+
+	var products []models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]*models.Product, len(products))
+	for i := range products {
+		locked[products[i].ID] = &products[i]
+	}
+	for _, id := range ids {
+		if _, ok := locked[id]; !ok {
+			return nil, &ProductError{ProductID: id, Err: ErrProductNotFound}
+		}
+	}
+	return locked, nil
+}
+
+// CheckAvailable verifies a locked product is active and holds at least quantity
+func CheckAvailable(product *models.Product, quantity float64) error {
+	if product.Status != "active" {
+		return &ProductError{ProductID: product.ID, Err: ErrProductUnavailable}
+	}
+	if quantity > product.Quantity {
+		return &InsufficientStockError{
+			ProductID: product.ID,
+			CropName:  product.CropName,
+			Requested: quantity,
+			Available: product.Quantity,
+		}
+	}
+	return nil
+}
+
+// roundQuantity rounds to the two decimals stored by the quantity columns
+func roundQuantity(q float64) float64 {
+	return math.Round(q*100) / 100
+}
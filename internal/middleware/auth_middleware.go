@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/tokens"
 	"farmer-to-buyer-portal/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AuthRequired is a middleware that validates JWT token
@@ -35,9 +39,44 @@ func AuthRequired() gin.HandlerFunc {
 			return
 		}
 
-		// Set user_id and role in context
+		// Reject tokens of deactivated users and tokens issued before the user's last password reset
+		db := c.MustGet("db").(*gorm.DB)
+		var user models.User
+		if err := db.Select("id", "is_active", "token_version").Where("id = ?", claims.UserID).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			}
+			c.Abort()
+			return
+		}
+		if !user.IsActive {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Account is deactivated"})
+			c.Abort()
+			return
+		}
+		if user.TokenVersion != claims.TokenVersion {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked, please log in again"})
+			c.Abort()
+			return
+		}
+
+		// Reject tokens whose session was revoked
+		if err := tokens.CheckSession(db, claims.UserID, claims.SessionID); err != nil {
+			if errors.Is(err, tokens.ErrSessionNotFound) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked, please log in again"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			}
+			c.Abort()
+			return
+		}
+
+		// Set user_id, role and session_id in context
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
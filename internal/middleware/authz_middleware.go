@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"farmer-to-buyer-portal/internal/authz"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows the request through only when the authenticated user has
+// one of roles. It must run after AuthRequired.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+	message := "This endpoint is only available to: " + strings.Join(roles, ", ")
+
+	return func(c *gin.Context) {
+		role, ok := c.Get("role")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+		if !allowed[role.(string)] {
+			c.JSON(http.StatusForbidden, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission allows the request through only when the authenticated
+// user's role is granted permission by the authz policy. It must run after
+// AuthRequired.
+func RequirePermission(permission authz.Permission) gin.HandlerFunc {
+	message := "You do not have permission to " + authz.Describe(permission)
+
+	return func(c *gin.Context) {
+		role, ok := c.Get("role")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+		if !authz.Can(role.(string), permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
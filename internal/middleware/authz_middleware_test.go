@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"farmer-to-buyer-portal/internal/authz"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serve runs guard on a request made by a user with role, or by an anonymous
+// caller when role is empty, and returns the response status
+func serve(t *testing.T, guard gin.HandlerFunc, role string) int {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		if role != "" {
+			c.Set("role", role)
+		}
+		c.Next()
+	}, guard, func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w.Code
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission authz.Permission
+		role       string
+		want       int
+	}{
+		{"no user", authz.ProductsManage, "", http.StatusUnauthorized},
+		{"role lacks permission", authz.ProductsManage, authz.RoleBuyer, http.StatusForbidden},
+		{"admin does not inherit farmer permissions", authz.ProductsManage, authz.RoleAdmin, http.StatusForbidden},
+		{"unknown role", authz.OrdersView, "system", http.StatusForbidden},
+		{"role has permission", authz.ProductsManage, authz.RoleFarmer, http.StatusNoContent},
+		{"shared permission", authz.OrdersView, authz.RoleBuyer, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(t, RequirePermission(tt.permission), tt.role); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles []string
+		role  string
+		want  int
+	}{
+		{"no user", []string{authz.RoleAdmin}, "", http.StatusUnauthorized},
+		{"other role", []string{authz.RoleAdmin}, authz.RoleFarmer, http.StatusForbidden},
+		{"listed role", []string{authz.RoleAdmin}, authz.RoleAdmin, http.StatusNoContent},
+		{"one of several roles", []string{authz.RoleBuyer, authz.RoleFarmer}, authz.RoleFarmer, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(t, RequireRole(tt.roles...), tt.role); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
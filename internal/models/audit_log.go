@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLog records a single administrative action
+type AuditLog struct {
+	ID         string    `gorm:"type:char(36);primaryKey"`
+	ActorID    string    `gorm:"type:char(36);not null;index;column:actor_id"`
+	Action     string    `gorm:"type:varchar(64);not null;index"`
+	TargetType string    `gorm:"type:varchar(32);not null;column:target_type"`
+	TargetID   string    `gorm:"type:char(36);not null;index;column:target_id"`
+	Reason     string    `gorm:"type:varchar(500)"`
+	Details    string    `gorm:"type:text"` // JSON encoded
+	IPAddress  string    `gorm:"type:varchar(45);column:ip_address"`
+	CreatedAt  time.Time `gorm:"type:datetime(3);autoCreateTime;index"`
+}
+
+// TableName specifies the table name for AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeCreate generates UUID if not set
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = generateUUID()
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CropCategory groups crops, e.g. vegetables or pulses
+type CropCategory struct {
+	ID        string    `gorm:"type:char(36);primaryKey"`
+	Slug      string    `gorm:"type:varchar(50);not null;uniqueIndex"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for CropCategory model
+func (CropCategory) TableName() string {
+	return "crop_categories"
+}
+
+// BeforeCreate generates UUID if not set
+func (c *CropCategory) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = generateUUID()
+	}
+	return nil
+}
+
+// Crop is an entry of the crop catalog products link to. Name is the English
+// name; local names are kept as synonyms.
+type Crop struct {
+	ID         string        `gorm:"type:char(36);primaryKey"`
+	CategoryID string        `gorm:"type:char(36);not null;index;column:category_id"`
+	Slug       string        `gorm:"type:varchar(100);not null;uniqueIndex"`
+	Name       string        `gorm:"type:varchar(100);not null;uniqueIndex"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time     `gorm:"autoUpdateTime"`
+	Category   CropCategory  `gorm:"foreignKey:CategoryID;references:ID;constraint:OnDelete:RESTRICT"`
+	Varieties  []CropVariety `gorm:"foreignKey:CropID;references:ID"`
+	Synonyms   []CropSynonym `gorm:"foreignKey:CropID;references:ID"`
+}
+
+// TableName specifies the table name for Crop model
+func (Crop) TableName() string {
+	return "crops"
+}
+
+// BeforeCreate generates UUID if not set
+func (c *Crop) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = generateUUID()
+	}
+	return nil
+}
+
+// CropVariety is a named variety of a crop, e.g. Alphonso for mango
+type CropVariety struct {
+	ID        string    `gorm:"type:char(36);primaryKey"`
+	CropID    string    `gorm:"type:char(36);not null;uniqueIndex:idx_crop_variety;column:crop_id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_crop_variety"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	Crop      Crop      `gorm:"foreignKey:CropID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for CropVariety model
+func (CropVariety) TableName() string {
+	return "crop_varieties"
+}
+
+// BeforeCreate generates UUID if not set
+func (v *CropVariety) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = generateUUID()
+	}
+	return nil
+}
+
+// CropSynonym is another name a crop is known by, in native script or transliterated
+type CropSynonym struct {
+	ID        string    `gorm:"type:char(36);primaryKey"`
+	CropID    string    `gorm:"type:char(36);not null;uniqueIndex:idx_crop_synonym;column:crop_id"`
+	Language  string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_crop_synonym"` // ISO 639-1 code
+	Term      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_crop_synonym;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	Crop      Crop      `gorm:"foreignKey:CropID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for CropSynonym model
+func (CropSynonym) TableName() string {
+	return "crop_synonyms"
+}
+
+// BeforeCreate generates UUID if not set
+func (s *CropSynonym) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = generateUUID()
+	}
+	return nil
+}
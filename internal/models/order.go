@@ -3,22 +3,28 @@ package models
 import (
 	"time"
 
+	"farmer-to-buyer-portal/internal/money"
+
 	"gorm.io/gorm"
 )
 
 // Order represents an order placed by a buyer
 type Order struct {
-	ID           string       `gorm:"type:char(36);primaryKey"`
-	BuyerID      string       `gorm:"type:char(36);not null;index;column:buyer_id"`
-	FarmerID     string       `gorm:"type:char(36);not null;index;column:farmer_id"`
-	Status       string       `gorm:"type:enum('pending','accepted','rejected','shipped','delivered');default:'pending'"`
-	DeliveryMode string       `gorm:"type:enum('pickup','courier');not null;column:delivery_mode"`
-	TotalAmount  float64      `gorm:"type:decimal(10,2);not null;column:total_amount"`
-	CreatedAt    time.Time    `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time    `gorm:"autoUpdateTime"`
-	Buyer        User         `gorm:"foreignKey:BuyerID;references:ID;constraint:OnDelete:CASCADE"`
-	Farmer       User         `gorm:"foreignKey:FarmerID;references:ID;constraint:OnDelete:CASCADE"`
-	OrderItems   []OrderItem  `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	ID                    string             `gorm:"type:char(36);primaryKey"`
+	BuyerID               string             `gorm:"type:char(36);not null;index;column:buyer_id"`
+	FarmerID              string             `gorm:"type:char(36);not null;index;column:farmer_id"`
+	CheckoutID            string             `gorm:"type:char(36);index;column:checkout_id"`
+	Status                string             `gorm:"type:enum('pending','accepted','rejected','shipped','delivered','cancelled');default:'pending'"`
+	DeliveryMode          string             `gorm:"type:enum('pickup','courier');not null;column:delivery_mode"`
+	TotalAmount           money.Paise        `gorm:"type:bigint;not null;column:total_amount_paise"` // sum of rounded line totals
+	CancellationRequested bool               `gorm:"default:false;column:cancellation_requested"`    // buyer asked to cancel an accepted order
+	CancellationReason    string             `gorm:"type:varchar(500);column:cancellation_reason"`
+	CreatedAt             time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt             time.Time          `gorm:"autoUpdateTime"`
+	Buyer                 User               `gorm:"foreignKey:BuyerID;references:ID;constraint:OnDelete:CASCADE"`
+	Farmer                User               `gorm:"foreignKey:FarmerID;references:ID;constraint:OnDelete:CASCADE"`
+	OrderItems            []OrderItem        `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	StatusEvents          []OrderStatusEvent `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
 }
 
 // TableName specifies the table name for Order model
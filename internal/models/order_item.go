@@ -3,20 +3,22 @@ package models
 import (
 	"time"
 
+	"farmer-to-buyer-portal/internal/money"
+
 	"gorm.io/gorm"
 )
 
 // OrderItem represents an item within an order
 type OrderItem struct {
-	ID           string    `gorm:"type:char(36);primaryKey"`
-	OrderID      string    `gorm:"type:char(36);not null;index;column:order_id"`
-	ProductID    string    `gorm:"type:char(36);not null;index;column:product_id"`
-	Quantity     float64   `gorm:"type:decimal(10,2);not null"`
-	PricePerUnit float64   `gorm:"type:decimal(10,2);not null;column:price_per_unit"`
-	CreatedAt    time.Time `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
-	Order        Order     `gorm:"foreignKey:OrderID;references:ID;constraint:OnDelete:CASCADE"`
-	Product      Product   `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE"`
+	ID           string      `gorm:"type:char(36);primaryKey"`
+	OrderID      string      `gorm:"type:char(36);not null;index;column:order_id"`
+	ProductID    string      `gorm:"type:char(36);not null;index;column:product_id"`
+	Quantity     float64     `gorm:"type:decimal(10,2);not null"`
+	PricePerUnit money.Paise `gorm:"type:bigint;not null;column:price_per_unit_paise"`
+	CreatedAt    time.Time   `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time   `gorm:"autoUpdateTime"`
+	Order        Order       `gorm:"foreignKey:OrderID;references:ID;constraint:OnDelete:CASCADE"`
+	Product      Product     `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE"`
 }
 
 // TableName specifies the table name for OrderItem model
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrderStatusEvent records a single order status transition for the audit trail
+type OrderStatusEvent struct {
+	ID         string    `gorm:"type:char(36);primaryKey"`
+	OrderID    string    `gorm:"type:char(36);not null;index;column:order_id"`
+	ActorID    string    `gorm:"type:char(36);index;column:actor_id"` // empty for system actions
+	ActorRole  string    `gorm:"type:varchar(20);not null;column:actor_role"`
+	FromStatus string    `gorm:"type:varchar(20);column:from_status"`
+	ToStatus   string    `gorm:"type:varchar(20);not null;column:to_status"`
+	Note       string    `gorm:"type:varchar(500)"`
+	CreatedAt  time.Time `gorm:"type:datetime(3);autoCreateTime;index"`
+}
+
+// TableName specifies the table name for OrderStatusEvent model
+func (OrderStatusEvent) TableName() string {
+	return "order_status_events"
+}
+
+// BeforeCreate generates UUID if not set
+func (e *OrderStatusEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = generateUUID()
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OTPCode is a hashed one-time code sent to a phone number
+type OTPCode struct {
+	ID         string     `gorm:"type:char(36);primaryKey"`
+	Phone      string     `gorm:"type:varchar(20);not null;index:idx_otp_phone_purpose"`
+	Purpose    string     `gorm:"type:varchar(30);not null;index:idx_otp_phone_purpose"`
+	CodeHash   string     `gorm:"type:varchar(255);not null;column:code_hash"`
+	Attempts   int        `gorm:"not null;default:0"`
+	ExpiresAt  time.Time  `gorm:"not null;column:expires_at"`
+	ConsumedAt *time.Time `gorm:"column:consumed_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime;index"`
+}
+
+// TableName specifies the table name for OTPCode model
+func (OTPCode) TableName() string {
+	return "otp_codes"
+}
+
+// BeforeCreate generates UUID if not set
+func (o *OTPCode) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = generateUUID()
+	}
+	return nil
+}
+
+// OTPThrottle counts the code requests for one phone and purpose. Its row is
+// locked while a code is issued, so concurrent requests cannot both pass the limit.
+type OTPThrottle struct {
+	Phone           string    `gorm:"type:varchar(20);primaryKey"`
+	Purpose         string    `gorm:"type:varchar(30);primaryKey"`
+	WindowStartedAt time.Time `gorm:"not null;column:window_started_at"` // start of the hour being counted
+	Requests        int       `gorm:"not null;default:0"`                // requests accepted since WindowStartedAt
+	LastRequestAt   time.Time `gorm:"not null;column:last_request_at"`
+}
+
+// TableName specifies the table name for OTPThrottle model
+func (OTPThrottle) TableName() string {
+	return "otp_throttles"
+}
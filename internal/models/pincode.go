@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Pincode is the approximate centre of an Indian PIN code area, loaded from
+// reference data by package geo
+type Pincode struct {
+	Code      string    `gorm:"type:char(6);primaryKey"`
+	Latitude  float64   `gorm:"type:double;not null"`
+	Longitude float64   `gorm:"type:double;not null"`
+	District  string    `gorm:"type:varchar(100)"`
+	State     string    `gorm:"type:varchar(100)"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for Pincode model
+func (Pincode) TableName() string {
+	return "pincodes"
+}
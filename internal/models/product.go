@@ -3,24 +3,42 @@ package models
 import (
 	"time"
 
+	"farmer-to-buyer-portal/internal/money"
+
 	"gorm.io/gorm"
 )
 
 // Product represents a product listing by a farmer
 type Product struct {
-	ID           string    `gorm:"type:char(36);primaryKey"`
-	FarmerID     string    `gorm:"type:char(36);not null;index;column:farmer_id"`
-	CropName     string    `gorm:"type:varchar(255);not null;index;column:crop_name"`
-	Quantity     float64   `gorm:"type:decimal(10,2);not null"`
-	Unit         string    `gorm:"type:varchar(50);not null"`
-	PricePerUnit float64   `gorm:"type:decimal(10,2);not null;column:price_per_unit"`
-	State        string    `gorm:"type:varchar(100);not null"`
-	City         string    `gorm:"type:varchar(100);not null"`
-	Pincode      string    `gorm:"type:varchar(10);not null;index"`
-	Status       string    `gorm:"type:enum('active','closed','sold');default:'active'"`
-	CreatedAt    time.Time `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
-	Farmer       User      `gorm:"foreignKey:FarmerID;references:ID;constraint:OnDelete:CASCADE"`
+	ID               string       `gorm:"type:char(36);primaryKey"`
+	FarmerID         string       `gorm:"type:char(36);not null;index;column:farmer_id"`
+	CropName         string       `gorm:"type:varchar(255);not null;index;index:ft_products_search,class:FULLTEXT;column:crop_name"`
+	CropID           *string      `gorm:"type:char(36);index;column:crop_id"` // catalog entry; nil for unmatched names
+	VarietyID        *string      `gorm:"type:char(36);index;column:variety_id"`
+	Quantity         float64      `gorm:"type:decimal(10,2);not null"` // available, excluding reserved stock
+	ReservedQuantity float64      `gorm:"type:decimal(10,2);not null;default:0;column:reserved_quantity"`
+	Unit             string       `gorm:"type:varchar(50);not null"` // canonical code from package units
+	PricePerUnit     money.Paise  `gorm:"type:bigint;not null;column:price_per_unit_paise"`
+	PricePerKg       *money.Paise `gorm:"type:bigint;index;column:price_per_kg_paise"` // nil unless Unit measures mass
+	State            string       `gorm:"type:varchar(100);not null"`
+	City             string       `gorm:"type:varchar(100);not null"`
+	Pincode          string       `gorm:"type:varchar(10);not null;index"`
+	Latitude         *float64     `gorm:"type:double;index:idx_products_location"` // from Pincode; nil when it is not in the reference data
+	Longitude        *float64     `gorm:"type:double;index:idx_products_location"`
+	Status           string       `gorm:"type:enum('active','closed','sold','moderated');default:'active'"`
+	Description      string       `gorm:"type:text;index:ft_products_search,class:FULLTEXT"`
+	SearchKeywords   string       `gorm:"type:text;index:ft_products_search,class:FULLTEXT;column:search_keywords"` // see package search
+
+	// Admin moderation; see package moderation
+	ModerationState        string     `gorm:"type:varchar(20);index;column:moderation_state"` // flagged, hidden, removed or empty
+	ModerationReason       string     `gorm:"type:varchar(500);column:moderation_reason"`
+	ModeratedBy            string     `gorm:"type:char(36);column:moderated_by"`
+	ModeratedAt            *time.Time `gorm:"column:moderated_at"`
+	StatusBeforeModeration string     `gorm:"type:varchar(20);column:status_before_moderation"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	Farmer    User      `gorm:"foreignKey:FarmerID;references:ID;constraint:OnDelete:CASCADE"`
 }
 
 // TableName specifies the table name for Product model
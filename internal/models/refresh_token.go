@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshToken is a hashed, single-use refresh token. Tokens rotated from the
+// same login share a FamilyID, which is also the sid of their access tokens.
+type RefreshToken struct {
+	ID         string     `gorm:"type:char(36);primaryKey"`
+	UserID     string     `gorm:"type:char(36);not null;index;column:user_id"`
+	FamilyID   string     `gorm:"type:char(36);not null;index;column:family_id"`
+	TokenHash  string     `gorm:"type:char(64);not null;uniqueIndex;column:token_hash"`
+	ExpiresAt  time.Time  `gorm:"not null;column:expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	ReplacedBy string     `gorm:"type:char(36);column:replaced_by"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	User       User       `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for RefreshToken model
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// BeforeCreate generates UUID if not set
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = generateUUID()
+	}
+	return nil
+}
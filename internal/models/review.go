@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Review represents a buyer's rating of a farmer for one delivered order
+type Review struct {
+	ID        string     `gorm:"type:char(36);primaryKey"`
+	OrderID   string     `gorm:"type:char(36);not null;uniqueIndex;column:order_id"`
+	FarmerID  string     `gorm:"type:char(36);not null;index;column:farmer_id"`
+	BuyerID   string     `gorm:"type:char(36);not null;index;column:buyer_id"`
+	Rating    int        `gorm:"type:tinyint;not null"`
+	Comment   string     `gorm:"type:text"`
+	Reply     string     `gorm:"type:text"`
+	RepliedAt *time.Time `gorm:"column:replied_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+	Order     Order      `gorm:"foreignKey:OrderID;references:ID;constraint:OnDelete:CASCADE"`
+	Buyer     User       `gorm:"foreignKey:BuyerID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for Review model
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// BeforeCreate generates UUID if not set
+func (r *Review) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateUUID()
+	}
+	return nil
+}
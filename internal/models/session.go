@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session is a login on one device. Its ID is the sid claim of every access
+// token issued for it and the family ID of its refresh tokens.
+type Session struct {
+	ID         string     `gorm:"type:char(36);primaryKey"`
+	UserID     string     `gorm:"type:char(36);not null;index;column:user_id"`
+	UserAgent  string     `gorm:"type:varchar(255);column:user_agent"`
+	IPAddress  string     `gorm:"type:varchar(45);column:ip_address"`
+	LastSeenAt time.Time  `gorm:"not null;column:last_seen_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	User       User       `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for Session model
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// BeforeCreate generates UUID if not set
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = generateUUID()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StockReservation holds product quantity against a pending order until the
+// farmer accepts it, rejects it, or the hold expires
+type StockReservation struct {
+	ID        string    `gorm:"type:char(36);primaryKey"`
+	OrderID   string    `gorm:"type:char(36);not null;index;column:order_id"`
+	ProductID string    `gorm:"type:char(36);not null;index;column:product_id"`
+	Quantity  float64   `gorm:"type:decimal(10,2);not null"`
+	Status    string    `gorm:"type:enum('held','committed','released','expired');default:'held';index"`
+	ExpiresAt time.Time `gorm:"not null;index;column:expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	Order     Order     `gorm:"foreignKey:OrderID;references:ID;constraint:OnDelete:CASCADE"`
+	Product   Product   `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for StockReservation model
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
+
+// BeforeCreate generates UUID if not set
+func (r *StockReservation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateUUID()
+	}
+	return nil
+}
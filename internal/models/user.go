@@ -6,9 +6,12 @@ type User struct {
 	Phone        string `gorm:"type:varchar(20);uniqueIndex;not null"`
 	Name         string `gorm:"type:varchar(255);not null"`
 	PasswordHash string `gorm:"type:varchar(255);not null" json:"-"`
-	Role         string `gorm:"type:varchar(20);not null"` // farmer, buyer, admin
+	Role         string `gorm:"type:enum('farmer','buyer','admin');not null"`
 	IsVerified   bool   `gorm:"default:false"`
 	IsActive     bool   `gorm:"default:true"`
+	TokenVersion int    `gorm:"not null;default:0;column:token_version"` // bumped to invalidate all issued tokens
+	// MustResetPassword is set by admins; login is refused until the password is reset
+	MustResetPassword bool `gorm:"not null;default:false;column:must_reset_password"`
 }
 
 // TableName specifies the table name for User model
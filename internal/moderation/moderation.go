@@ -0,0 +1,181 @@
+// Package moderation implements the admin takedown workflow for product listings.
+//
+// A flagged listing stays public while it is reviewed. Hidden and removed
+// listings move to the 'moderated' product status, which keeps them out of
+// public listings and blocks new orders; only their owner and admins can see
+// them. Hidden listings can still be edited by the farmer, removed listings are
+// locked. Restoring puts the listing back in the status it had before.
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"farmer-to-buyer-portal/internal/audit"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/notify"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StatusModerated is the product status of hidden and removed listings
+const StatusModerated = "moderated"
+
+// Moderation states stored in Product.ModerationState
+const (
+	StateFlagged = "flagged"
+	StateHidden  = "hidden"
+	StateRemoved = "removed"
+)
+
+// Actions an admin can take on a listing
+const (
+	ActionFlag    = "flag"
+	ActionHide    = "hide"
+	ActionRemove  = "remove"
+	ActionRestore = "restore"
+)
+
+// actionStates maps each takedown action to the state it puts the listing in
+var actionStates = map[string]string{
+	ActionFlag:   StateFlagged,
+	ActionHide:   StateHidden,
+	ActionRemove: StateRemoved,
+}
+
+var (
+	// ErrInvalidAction is returned for an unknown moderation action
+	ErrInvalidAction = errors.New("action must be one of flag, hide, remove, restore")
+	// ErrReasonRequired is returned when a takedown action has no reason
+	ErrReasonRequired = errors.New("a reason is required")
+	// ErrNotModerated is returned when restoring a listing that is not moderated
+	ErrNotModerated = errors.New("product is not under moderation")
+	// ErrProductNotFound is returned when the listing does not exist
+	ErrProductNotFound = errors.New("product not found")
+)
+
+// Request describes one moderation decision
+type Request struct {
+	Action    string
+	Reason    string
+	ActorID   string
+	IPAddress string
+}
+
+// Apply locks the product, applies req and records it in the audit log.
+// It returns the updated product.
+func Apply(tx *gorm.DB, productID string, req Request) (models.Product, error) {
+	var product models.Product
+
+	state, takedown := actionStates[req.Action]
+	if !takedown && req.Action != ActionRestore {
+		return product, ErrInvalidAction
+	}
+	if takedown && req.Reason == "" {
+		return product, ErrReasonRequired
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", productID).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return product, ErrProductNotFound
+		}
+		return product, err
+	}
+
+	now := time.Now()
+	var updates map[string]interface{}
+	if takedown {
+		updates = map[string]interface{}{
+			"moderation_state":  state,
+			"moderation_reason": req.Reason,
+			"moderated_by":      req.ActorID,
+			"moderated_at":      now,
+		}
+		switch {
+		case state != StateFlagged && product.Status != StatusModerated:
+			updates["status_before_moderation"] = product.Status
+			updates["status"] = StatusModerated
+		case state == StateFlagged && product.Status == StatusModerated:
+			// Downgrading a takedown to a flag makes the listing public again
+			updates["status_before_moderation"] = ""
+			updates["status"] = restoredStatus(product)
+		}
+	} else {
+		if product.ModerationState == "" {
+			return product, ErrNotModerated
+		}
+		updates = map[string]interface{}{
+			"moderation_state":         "",
+			"moderation_reason":        "",
+			"moderated_by":             req.ActorID,
+			"moderated_at":             now,
+			"status_before_moderation": "",
+		}
+		if product.Status == StatusModerated {
+			updates["status"] = restoredStatus(product)
+		}
+	}
+
+	if err := tx.Model(&product).Updates(updates).Error; err != nil {
+		return product, err
+	}
+
+	details := map[string]interface{}{"status": product.Status}
+	if state != "" {
+		details["moderation_state"] = state
+	}
+	if err := audit.Record(tx, audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     "product." + req.Action,
+		TargetType: audit.TargetProduct,
+		TargetID:   product.ID,
+		Reason:     req.Reason,
+		Details:    details,
+		IPAddress:  req.IPAddress,
+	}); err != nil {
+		return product, err
+	}
+
+	return product, nil
+}
+
+// NotifyFarmer tells the listing's owner about a moderation decision. Call it
+// after the transaction that applied the decision has committed.
+func NotifyFarmer(product models.Product, req Request) {
+	var subject, message string
+	switch req.Action {
+	case ActionFlag:
+		subject = "Listing flagged for review"
+		message = fmt.Sprintf("Your %s listing has been flagged for review: %s", product.CropName, req.Reason)
+	case ActionHide:
+		subject = "Listing hidden"
+		message = fmt.Sprintf("Your %s listing has been hidden from buyers: %s. Update the listing to address this.", product.CropName, req.Reason)
+	case ActionRemove:
+		subject = "Listing removed"
+		message = fmt.Sprintf("Your %s listing has been removed: %s", product.CropName, req.Reason)
+	case ActionRestore:
+		subject = "Listing restored"
+		message = fmt.Sprintf("Your %s listing is visible to buyers again.", product.CropName)
+	default:
+		return
+	}
+	notify.Send(product.FarmerID, subject, message)
+}
+
+// restoredStatus picks the status a moderated listing returns to, correcting
+// for stock that sold or was released while it was hidden
+func restoredStatus(product models.Product) string {
+	status := product.StatusBeforeModeration
+	if status == "" {
+		status = "active"
+	}
+	switch {
+	case status == "active" && product.Quantity <= 0:
+		return "sold"
+	case status == "sold" && product.Quantity > 0:
+		return "active"
+	}
+	return status
+}
@@ -0,0 +1,207 @@
+// Package money represents rupee amounts as integer paise so prices and totals
+// never pass through binary floating point.
+//
+// Rounding rules:
+//   - Amounts entered by users must have at most two decimal places; anything
+//     finer is rejected rather than silently rounded.
+//   - A line total (price × quantity) is rounded to the nearest paisa, with
+//     halves rounded away from zero. This is the only place rounding happens.
+//   - An order total is the exact sum of its rounded line totals.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Paise is an amount of Indian rupees in paise (1 rupee = 100 paise)
+type Paise int64
+
+var (
+	// ErrInvalidAmount is returned for text that is not a decimal rupee amount
+	ErrInvalidAmount = errors.New("amount must be a decimal number of rupees")
+	// ErrTooPrecise is returned for amounts with more than two decimal places
+	ErrTooPrecise = errors.New("amount cannot have more than two decimal places")
+	// ErrOutOfRange is returned for amounts that do not fit in Paise
+	ErrOutOfRange = errors.New("amount is out of range")
+)
+
+// Rupees returns a whole number of rupees as Paise
+func Rupees(rupees int64) Paise {
+	return Paise(rupees * 100)
+}
+
+// Parse reads a decimal rupee amount such as "120", "120.5" or "-0.75"
+// exactly, without going through float64
+func Parse(s string) (Paise, error) {
+	s = strings.TrimSpace(s)
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || hasPoint && fraction == "" {
+		return 0, ErrInvalidAmount
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return 0, ErrInvalidAmount
+	}
+	if len(strings.TrimRight(fraction, "0")) > 2 {
+		return 0, ErrTooPrecise
+	}
+	fraction = (fraction + "00")[:2]
+	if whole == "" {
+		whole = "0"
+	}
+
+	rupees, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || rupees > (math.MaxInt64-99)/100 {
+		return 0, ErrOutOfRange
+	}
+	paise, _ := strconv.ParseInt(fraction, 10, 64)
+
+	amount := Paise(rupees*100 + paise)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// MulQuantity returns price × quantity rounded to the nearest paisa, halves
+// away from zero. quantity is a stock quantity with at most two decimals. It
+// returns ErrOutOfRange when the result does not fit in Paise.
+func MulQuantity(price Paise, quantity float64) (Paise, error) {
+	// Quantities are stored as decimal(10,2), so hundredths are exact integers
+	scaled := math.Round(quantity * 100)
+	if math.IsNaN(scaled) || scaled >= math.MaxInt64 || scaled <= math.MinInt64 {
+		return 0, ErrOutOfRange
+	}
+	product, ok := mul(int64(price), int64(scaled))
+	if !ok {
+		return 0, ErrOutOfRange
+	}
+
+	quotient, remainder := product/100, product%100
+	switch {
+	case remainder >= 50:
+		quotient++
+	case remainder <= -50:
+		quotient--
+	}
+	return Paise(quotient), nil
+}
+
+// Add returns a + b, or ErrOutOfRange when the sum does not fit in Paise
+func Add(a, b Paise) (Paise, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, ErrOutOfRange
+	}
+	return sum, nil
+}
+
+// mul returns a × b and whether it fits in an int64
+func mul(a, b int64) (int64, bool) {
+	negative := (a < 0) != (b < 0)
+	hi, lo := bits.Mul64(magnitude(a), magnitude(b))
+	switch {
+	case hi != 0:
+		return 0, false
+	case negative && lo <= 1<<63:
+		return int64(-lo), true
+	case !negative && lo < 1<<63:
+		return int64(lo), true
+	}
+	return 0, false
+}
+
+// magnitude returns |n| without overflowing on math.MinInt64
+func magnitude(n int64) uint64 {
+	if n < 0 {
+		return -uint64(n)
+	}
+	return uint64(n)
+}
+
+// Rupees returns the amount in rupees, for display and external APIs only
+func (p Paise) Rupees() float64 {
+	return float64(p) / 100
+}
+
+// String formats the amount as rupees with exactly two decimals, e.g. "1234.50"
+func (p Paise) String() string {
+	sign := ""
+	value := int64(p)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+// MarshalJSON encodes the amount as a rupee number with two decimals
+func (p Paise) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON accepts a rupee amount as a JSON number or string
+func (p *Paise) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	if strings.ContainsAny(text, "eE") {
+		return ErrInvalidAmount
+	}
+	amount, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*p = amount
+	return nil
+}
+
+// Value stores the amount as an integer number of paise
+func (p Paise) Value() (driver.Value, error) {
+	return int64(p), nil
+}
+
+// Scan reads an integer number of paise from the database
+func (p *Paise) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		*p = Paise(v)
+	case []byte:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("money: cannot scan %q into Paise: %w", v, err)
+		}
+		*p = Paise(n)
+	case nil:
+		*p = 0
+	default:
+		return fmt.Errorf("money: cannot scan %T into Paise", value)
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMulQuantity(t *testing.T) {
+	tests := []struct {
+		price    Paise
+		quantity float64
+		want     Paise
+	}{
+		{Rupees(40), 2.5, Rupees(100)},
+		{333, 0.5, 167},   // 166.5 rounds away from zero
+		{-333, 0.5, -167}, // and so does -166.5
+		{1999, 0.33, 660}, // 659.67
+		{Rupees(12), 0, 0},
+		{math.MaxInt64, 0.01, 92233720368547758},
+	}
+	for _, tt := range tests {
+		got, err := MulQuantity(tt.price, tt.quantity)
+		if err != nil || got != tt.want {
+			t.Errorf("MulQuantity(%d, %v) = %d, %v, want %d", tt.price, tt.quantity, got, err, tt.want)
+		}
+	}
+}
+
+func TestMulQuantityOverflow(t *testing.T) {
+	tests := []struct {
+		price    Paise
+		quantity float64
+	}{
+		{math.MaxInt64, 1},
+		{math.MaxInt64 / 50, 1},
+		{math.MinInt64, 1},
+		{Rupees(1_000_000_000), 1e8},
+		{Rupees(1), math.Inf(1)},
+		{Rupees(1), math.NaN()},
+		{Rupees(1), 1e17},
+	}
+	for _, tt := range tests {
+		if got, err := MulQuantity(tt.price, tt.quantity); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("MulQuantity(%d, %v) = %d, %v, want ErrOutOfRange", tt.price, tt.quantity, got, err)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	if got, err := Add(Rupees(1), 50); err != nil || got != 150 {
+		t.Errorf("Add(100, 50) = %d, %v, want 150", got, err)
+	}
+	if _, err := Add(math.MaxInt64, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Add(MaxInt64, 1) error = %v, want ErrOutOfRange", err)
+	}
+	if _, err := Add(math.MinInt64, -1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Add(MinInt64, -1) error = %v, want ErrOutOfRange", err)
+	}
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"log"
+	"sync"
+)
+
+// Notifier delivers a short message to a user
+type Notifier interface {
+	Notify(userID, subject, message string) error
+}
+
+// LogNotifier writes notifications to the application log. It is the default
+// until a real delivery channel is configured.
+type LogNotifier struct{}
+
+// Notify logs the notification
+func (LogNotifier) Notify(userID, subject, message string) error {
+	log.Printf("INFO: notification to user %s - %s: %s", userID, subject, message)
+	return nil
+}
+
+var (
+	mu       sync.RWMutex
+	notifier Notifier = LogNotifier{}
+)
+
+// SetDefault replaces the notifier used by Send
+func SetDefault(n Notifier) {
+	mu.Lock()
+	defer mu.Unlock()
+	notifier = n
+}
+
+// Send delivers a notification in the background. Failures are logged and
+// never block the caller.
+func Send(userID, subject, message string) {
+	if userID == "" {
+		return
+	}
+
+	mu.RLock()
+	n := notifier
+	mu.RUnlock()
+
+	go func() {
+		if err := n.Notify(userID, subject, message); err != nil {
+			log.Printf("ERROR: failed to notify user %s: %v", userID, err)
+		}
+	}()
+}
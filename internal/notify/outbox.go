@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+type outboxKey struct{}
+
+type pending struct {
+	userID, subject, message string
+}
+
+// Outbox holds notifications until the database transaction that produced
+// them commits, so a rolled-back change never notifies anyone
+type Outbox struct {
+	mu      sync.Mutex
+	pending []pending
+}
+
+// WithOutbox returns a copy of ctx carrying outbox
+func WithOutbox(ctx context.Context, outbox *Outbox) context.Context {
+	return context.WithValue(ctx, outboxKey{}, outbox)
+}
+
+// OutboxFrom returns the outbox carried by ctx, or nil
+func OutboxFrom(ctx context.Context) *Outbox {
+	if ctx == nil {
+		return nil
+	}
+	outbox, _ := ctx.Value(outboxKey{}).(*Outbox)
+	return outbox
+}
+
+// Add queues a notification
+func (o *Outbox) Add(userID, subject, message string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending = append(o.pending, pending{userID: userID, subject: subject, message: message})
+}
+
+// Flush sends the queued notifications and empties the outbox. Call it only
+// after the transaction has committed.
+func (o *Outbox) Flush() {
+	o.mu.Lock()
+	queued := o.pending
+	o.pending = nil
+	o.mu.Unlock()
+
+	for _, n := range queued {
+		Send(n.userID, n.subject, n.message)
+	}
+}
@@ -0,0 +1,86 @@
+package orderstate
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"farmer-to-buyer-portal/internal/inventory"
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExpireStale releases every stock hold of a pending order that has outlived
+// its window and rejects the order. It returns the number of orders expired.
+//
+// Rows are locked in the same order as every other order transition: the
+// order, then its reservations, then their products.
+func ExpireStale(db *gorm.DB, now time.Time) (int, error) {
+	var orderIDs []string
+	if err := db.Model(&models.StockReservation{}).
+		Joins("JOIN orders ON orders.id = stock_reservations.order_id").
+		Where("stock_reservations.status = ? AND stock_reservations.expires_at <= ?", inventory.ReservationHeld, now).
+		Where("orders.status = ?", string(Pending)).
+		Distinct().
+		Pluck("stock_reservations.order_id", &orderIDs).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, orderID := range orderIDs {
+		changed := false
+		err := Run(db, func(tx *gorm.DB) error {
+			var order models.Order
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND status = ?", orderID, string(Pending)).
+				First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					// Accepted, cancelled or rejected since the lookup
+					return nil
+				}
+				return err
+			}
+			if err := inventory.Release(tx, order.ID, inventory.ReservationExpired); err != nil {
+				return err
+			}
+			if err := Default.Transition(tx, &order, Rejected, System, "Stock reservation expired before the farmer accepted the order"); err != nil {
+				return err
+			}
+			changed = true
+			return nil
+		})
+		if err != nil {
+			log.Printf("ERROR: failed to expire stock reservations for order %s: %v", orderID, err)
+			continue
+		}
+		if changed {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// RunSweeper expires stale holds every interval until ctx is cancelled
+func RunSweeper(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, err := ExpireStale(db, now)
+			if err != nil {
+				log.Printf("ERROR: stock reservation sweep failed: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("INFO: expired stock reservations for %d pending order(s)", expired)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package orderstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/inventory"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/notify"
+	"farmer-to-buyer-portal/internal/ratings"
+
+	"gorm.io/gorm"
+)
+
+// Order states
+const (
+	Pending   State = "pending"
+	Accepted  State = "accepted"
+	Rejected  State = "rejected"
+	Shipped   State = "shipped"
+	Delivered State = "delivered"
+	Cancelled State = "cancelled"
+)
+
+// Roles that may trigger transitions
+const (
+	RoleBuyer  = authz.RoleBuyer
+	RoleFarmer = authz.RoleFarmer
+	RoleSystem = "system"
+)
+
+// System is the actor used for automatic transitions such as hold expiry
+var System = Actor{Role: RoleSystem}
+
+// ErrCancellationPending is returned when shipping an order the buyer asked to cancel
+var ErrCancellationPending = errors.New("the buyer's cancellation request must be resolved first")
+
+// ErrNoCancellationRequest is returned when approving a cancellation nobody asked for
+var ErrNoCancellationRequest = errors.New("there is no pending cancellation request for this order")
+
+// Default is the order lifecycle used by the API. Adding a state means adding
+// it to the list below and declaring its rules.
+var Default = New(
+	[]State{Pending, Accepted, Rejected, Shipped, Delivered, Cancelled},
+
+	Allow(Pending, Accepted, RoleFarmer).Before(commitStock),
+	Allow(Pending, Rejected, RoleFarmer, RoleSystem).Before(releaseStock),
+	Allow(Pending, Cancelled, RoleBuyer).Before(releaseStock).After(storeCancellationReason),
+	Allow(Accepted, Shipped, RoleFarmer).Before(requireNoCancellationRequest),
+	Allow(Accepted, Cancelled, RoleFarmer).Before(requireCancellationRequest, returnStock).After(clearCancellationRequest),
+	Allow(Shipped, Delivered, RoleFarmer).After(refreshFarmerStats),
+).WithPost(notifyCounterparty)
+
+// commitStock turns the order's held stock into sold stock
+func commitStock(tx *gorm.DB, order *models.Order, _ Change) error {
+	return inventory.Commit(tx, order.ID)
+}
+
+// releaseStock returns the order's held stock to its listings
+func releaseStock(tx *gorm.DB, order *models.Order, _ Change) error {
+	return inventory.Release(tx, order.ID, inventory.ReservationReleased)
+}
+
+// returnStock puts stock already sold to the order back on its listings
+func returnStock(tx *gorm.DB, order *models.Order, _ Change) error {
+	return inventory.ReturnCommitted(tx, order.ID)
+}
+
+func requireNoCancellationRequest(_ *gorm.DB, order *models.Order, _ Change) error {
+	if order.CancellationRequested {
+		return ErrCancellationPending
+	}
+	return nil
+}
+
+func requireCancellationRequest(_ *gorm.DB, order *models.Order, _ Change) error {
+	if !order.CancellationRequested {
+		return ErrNoCancellationRequest
+	}
+	return nil
+}
+
+// storeCancellationReason keeps the buyer's note as the cancellation reason
+func storeCancellationReason(tx *gorm.DB, order *models.Order, change Change) error {
+	if change.Note == "" {
+		return nil
+	}
+	order.CancellationReason = change.Note
+	return tx.Model(order).Update("cancellation_reason", change.Note).Error
+}
+
+func clearCancellationRequest(tx *gorm.DB, order *models.Order, _ Change) error {
+	order.CancellationRequested = false
+	return tx.Model(order).Update("cancellation_requested", false).Error
+}
+
+// refreshFarmerStats updates the farmer's delivered order count
+func refreshFarmerStats(tx *gorm.DB, order *models.Order, _ Change) error {
+	return ratings.RefreshFarmerStats(tx, order.FarmerID)
+}
+
+// ErrNoOutbox is returned by a transition that was not started with Run, which
+// would have no way to hold its notifications until commit
+var ErrNoOutbox = errors.New("orderstate: order changes must run inside orderstate.Run")
+
+// Run runs fn in a transaction on db and, once it commits, sends the
+// notifications its order changes queued. Nothing is sent if fn fails.
+func Run(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	outbox := &notify.Outbox{}
+	if err := db.WithContext(notify.WithOutbox(ctx, outbox)).Transaction(fn); err != nil {
+		return err
+	}
+	outbox.Flush()
+	return nil
+}
+
+// NotifyCounterparty queues a notification for the side of the order the
+// actor is not on, to be sent when the transaction started by Run commits.
+// The system actor notifies both sides.
+func NotifyCounterparty(tx *gorm.DB, order *models.Order, actor Actor, subject, message string) error {
+	outbox := notify.OutboxFrom(tx.Statement.Context)
+	if outbox == nil {
+		return ErrNoOutbox
+	}
+	if actor.Role != RoleFarmer {
+		outbox.Add(order.FarmerID, subject, message)
+	}
+	if actor.Role != RoleBuyer {
+		outbox.Add(order.BuyerID, subject, message)
+	}
+	return nil
+}
+
+// notifyCounterparty tells the other side of the order about the new status
+func notifyCounterparty(tx *gorm.DB, order *models.Order, change Change) error {
+	subject := fmt.Sprintf("Order %s", change.To)
+	message := fmt.Sprintf("Order %s moved from %s to %s", order.ID, change.From, change.To)
+	return NotifyCounterparty(tx, order, change.Actor, subject, message)
+}
@@ -0,0 +1,204 @@
+package orderstate
+
+import (
+	"fmt"
+	"sort"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// State is an order status as stored in orders.status
+type State string
+
+// Actor identifies who triggered a transition
+type Actor struct {
+	ID   string
+	Role string
+}
+
+// Change describes a transition being applied to an order
+type Change struct {
+	From  State
+	To    State
+	Actor Actor
+	Note  string
+}
+
+// Hook runs inside the transition's database transaction. Returning an error
+// aborts the transition and rolls back everything done so far.
+type Hook func(tx *gorm.DB, order *models.Order, change Change) error
+
+// Rule declares one allowed transition, the roles that may trigger it and its hooks
+type Rule struct {
+	From  State
+	To    State
+	Roles []string
+	Pre   []Hook // run before the status is written
+	Post  []Hook // run after the status is written and the event recorded
+}
+
+// Allow starts a rule letting roles move an order from one state to another
+func Allow(from, to State, roles ...string) Rule {
+	return Rule{From: from, To: to, Roles: roles}
+}
+
+// Before appends pre-transition hooks to the rule
+func (r Rule) Before(hooks ...Hook) Rule {
+	r.Pre = append(append([]Hook(nil), r.Pre...), hooks...)
+	return r
+}
+
+// After appends post-transition hooks to the rule
+func (r Rule) After(hooks ...Hook) Rule {
+	r.Post = append(append([]Hook(nil), r.Post...), hooks...)
+	return r
+}
+
+func (r Rule) permits(role string) bool {
+	for _, allowed := range r.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError reports a transition that is not declared for the actor's role
+type TransitionError struct {
+	From    State
+	To      State
+	Role    string
+	Allowed []State
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition from %q to %q for role %q", e.From, e.To, e.Role)
+}
+
+// Machine holds the declared order states and transitions
+type Machine struct {
+	states []State
+	rules  map[State]map[State]Rule
+	post   []Hook
+}
+
+// New builds a machine from its states and rules. It panics if a rule refers to
+// a state that was not declared.
+func New(states []State, rules ...Rule) *Machine {
+	m := &Machine{
+		states: append([]State(nil), states...),
+		rules:  make(map[State]map[State]Rule),
+	}
+	known := make(map[State]bool, len(states))
+	for _, s := range states {
+		known[s] = true
+	}
+	for _, r := range rules {
+		if !known[r.From] || !known[r.To] {
+			panic(fmt.Sprintf("orderstate: rule %s -> %s uses an undeclared state", r.From, r.To))
+		}
+		if m.rules[r.From] == nil {
+			m.rules[r.From] = make(map[State]Rule)
+		}
+		m.rules[r.From][r.To] = r
+	}
+	return m
+}
+
+// WithPost registers hooks that run after every transition
+func (m *Machine) WithPost(hooks ...Hook) *Machine {
+	m.post = append(m.post, hooks...)
+	return m
+}
+
+// States returns the declared states in declaration order
+func (m *Machine) States() []State {
+	return append([]State(nil), m.states...)
+}
+
+// IsTerminal reports whether no transition leaves the state
+func (m *Machine) IsTerminal(s State) bool {
+	return len(m.rules[s]) == 0
+}
+
+// Allowed returns the states the role may move an order to from the given state
+func (m *Machine) Allowed(from State, role string) []State {
+	allowed := []State{}
+	for to, r := range m.rules[from] {
+		if r.permits(role) {
+			allowed = append(allowed, to)
+		}
+	}
+	sort.Slice(allowed, func(i, j int) bool { return m.index(allowed[i]) < m.index(allowed[j]) })
+	return allowed
+}
+
+// Can reports whether the role may move an order from one state to another
+func (m *Machine) Can(from, to State, role string) bool {
+	r, ok := m.rules[from][to]
+	return ok && r.permits(role)
+}
+
+// Transition moves order to the target state inside tx. It checks the rule,
+// runs the pre hooks, writes the status, records the status event and then runs
+// the post hooks. The caller is expected to have locked the order row.
+func (m *Machine) Transition(tx *gorm.DB, order *models.Order, to State, actor Actor, note string) error {
+	from := State(order.Status)
+	r, ok := m.rules[from][to]
+	if !ok || !r.permits(actor.Role) {
+		return &TransitionError{From: from, To: to, Role: actor.Role, Allowed: m.Allowed(from, actor.Role)}
+	}
+
+	change := Change{From: from, To: to, Actor: actor, Note: note}
+	for _, hook := range r.Pre {
+		if err := hook(tx, order, change); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(order).Update("status", string(to)).Error; err != nil {
+		return err
+	}
+	order.Status = string(to)
+
+	if err := RecordEvent(tx, order.ID, actor, from, to, note); err != nil {
+		return err
+	}
+
+	for _, hook := range r.Post {
+		if err := hook(tx, order, change); err != nil {
+			return err
+		}
+	}
+	for _, hook := range m.post {
+		if err := hook(tx, order, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordEvent appends an entry to the order's status timeline. It is used by
+// Transition and directly for notable events that do not change the status.
+func RecordEvent(tx *gorm.DB, orderID string, actor Actor, from, to State, note string) error {
+	event := models.OrderStatusEvent{
+		OrderID:    orderID,
+		ActorID:    actor.ID,
+		ActorRole:  actor.Role,
+		FromStatus: string(from),
+		ToStatus:   string(to),
+		Note:       note,
+	}
+	return tx.Create(&event).Error
+}
+
+func (m *Machine) index(s State) int {
+	for i, known := range m.states {
+		if known == s {
+			return i
+		}
+	}
+	return len(m.states)
+}
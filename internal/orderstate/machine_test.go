@@ -0,0 +1,426 @@
+package orderstate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/dbtest"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/notify"
+	"farmer-to-buyer-portal/internal/orderstate"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var roles = []string{orderstate.RoleBuyer, orderstate.RoleFarmer, orderstate.RoleSystem, authz.RoleAdmin}
+
+// defaultRules spells out the transitions Default must allow, independently of
+// how the machine declares them
+var defaultRules = map[orderstate.State]map[orderstate.State][]string{
+	orderstate.Pending: {
+		orderstate.Accepted:  {orderstate.RoleFarmer},
+		orderstate.Rejected:  {orderstate.RoleFarmer, orderstate.RoleSystem},
+		orderstate.Cancelled: {orderstate.RoleBuyer},
+	},
+	orderstate.Accepted: {
+		orderstate.Shipped:   {orderstate.RoleFarmer},
+		orderstate.Cancelled: {orderstate.RoleFarmer},
+	},
+	orderstate.Shipped: {
+		orderstate.Delivered: {orderstate.RoleFarmer},
+	},
+}
+
+func allowedByRule(from, to orderstate.State, role string) bool {
+	for _, r := range defaultRules[from][to] {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDefaultStates(t *testing.T) {
+	want := []orderstate.State{
+		orderstate.Pending, orderstate.Accepted, orderstate.Rejected,
+		orderstate.Shipped, orderstate.Delivered, orderstate.Cancelled,
+	}
+	if got := orderstate.Default.States(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("States() = %v, want %v", got, want)
+	}
+	for _, s := range want {
+		terminal := len(defaultRules[s]) == 0
+		if got := orderstate.Default.IsTerminal(s); got != terminal {
+			t.Errorf("IsTerminal(%s) = %v, want %v", s, got, terminal)
+		}
+	}
+}
+
+func TestDefaultCan(t *testing.T) {
+	for _, from := range orderstate.Default.States() {
+		for _, to := range orderstate.Default.States() {
+			for _, role := range roles {
+				want := allowedByRule(from, to, role)
+				if got := orderstate.Default.Can(from, to, role); got != want {
+					t.Errorf("Can(%s, %s, %s) = %v, want %v", from, to, role, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDefaultAllowed(t *testing.T) {
+	for _, from := range orderstate.Default.States() {
+		for _, role := range roles {
+			want := []orderstate.State{}
+			for _, to := range orderstate.Default.States() {
+				if allowedByRule(from, to, role) {
+					want = append(want, to)
+				}
+			}
+			if got := orderstate.Default.Allowed(from, role); !reflect.DeepEqual(got, want) {
+				t.Errorf("Allowed(%s, %s) = %v, want %v", from, role, got, want)
+			}
+		}
+	}
+}
+
+func TestDefaultTransitionRejectsUndeclaredMoves(t *testing.T) {
+	db, statements := dryRun(t)
+
+	for _, from := range orderstate.Default.States() {
+		for _, to := range orderstate.Default.States() {
+			for _, role := range roles {
+				if allowedByRule(from, to, role) {
+					continue
+				}
+				order := &models.Order{ID: "order-1", BuyerID: "buyer-1", FarmerID: "farmer-1", Status: string(from)}
+				err := orderstate.Default.Transition(db, order, to, orderstate.Actor{ID: "actor-1", Role: role}, "")
+
+				var transitionErr *orderstate.TransitionError
+				if !errors.As(err, &transitionErr) {
+					t.Errorf("Transition(%s -> %s, %s) error = %v, want *TransitionError", from, to, role, err)
+					continue
+				}
+				want := orderstate.TransitionError{From: from, To: to, Role: role, Allowed: orderstate.Default.Allowed(from, role)}
+				if !reflect.DeepEqual(*transitionErr, want) {
+					t.Errorf("Transition(%s -> %s, %s) error = %+v, want %+v", from, to, role, *transitionErr, want)
+				}
+				if order.Status != string(from) {
+					t.Errorf("Transition(%s -> %s, %s) changed the order status to %s", from, to, role, order.Status)
+				}
+			}
+		}
+	}
+	if got := statements.all(); len(got) > 0 {
+		t.Errorf("rejected transitions ran SQL: %v", got)
+	}
+}
+
+func TestTransitionRunsHooksInOrder(t *testing.T) {
+	db, statements := dryRun(t)
+
+	var calls []string
+	hook := func(name string) orderstate.Hook {
+		return func(_ *gorm.DB, order *models.Order, change orderstate.Change) error {
+			calls = append(calls, fmt.Sprintf("%s %s->%s status=%s", name, change.From, change.To, order.Status))
+			return nil
+		}
+	}
+	m := orderstate.New(
+		[]orderstate.State{orderstate.Pending, orderstate.Accepted},
+		orderstate.Allow(orderstate.Pending, orderstate.Accepted, orderstate.RoleFarmer).
+			Before(hook("before")).
+			After(hook("after")),
+	).WithPost(hook("post"))
+
+	order := &models.Order{ID: "order-1", Status: string(orderstate.Pending)}
+	if err := m.Transition(db, order, orderstate.Accepted, orderstate.Actor{ID: "farmer-1", Role: orderstate.RoleFarmer}, "ok"); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+
+	wantCalls := []string{
+		"before pending->accepted status=pending",
+		"after pending->accepted status=accepted",
+		"post pending->accepted status=accepted",
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("hook calls = %v, want %v", calls, wantCalls)
+	}
+
+	got := statements.all()
+	if len(got) != 2 || !strings.HasPrefix(got[0], "UPDATE `orders` SET `status`") ||
+		!strings.HasPrefix(got[1], "INSERT INTO `order_status_events`") {
+		t.Errorf("statements = %v, want the status update then the event insert", got)
+	}
+}
+
+func TestTransitionStopsAtFailingBeforeHook(t *testing.T) {
+	db, statements := dryRun(t)
+
+	errHook := errors.New("hook failed")
+	afterRan := false
+	m := orderstate.New(
+		[]orderstate.State{orderstate.Pending, orderstate.Accepted},
+		orderstate.Allow(orderstate.Pending, orderstate.Accepted, orderstate.RoleFarmer).
+			Before(func(*gorm.DB, *models.Order, orderstate.Change) error { return errHook }).
+			After(func(*gorm.DB, *models.Order, orderstate.Change) error { afterRan = true; return nil }),
+	)
+
+	order := &models.Order{ID: "order-1", Status: string(orderstate.Pending)}
+	err := m.Transition(db, order, orderstate.Accepted, orderstate.Actor{ID: "farmer-1", Role: orderstate.RoleFarmer}, "")
+	if !errors.Is(err, errHook) {
+		t.Fatalf("Transition() error = %v, want %v", err, errHook)
+	}
+	if order.Status != string(orderstate.Pending) {
+		t.Errorf("order status = %s, want pending", order.Status)
+	}
+	if afterRan {
+		t.Error("after hook ran although the before hook failed")
+	}
+	if got := statements.all(); len(got) > 0 {
+		t.Errorf("failed transition ran SQL: %v", got)
+	}
+}
+
+func TestNotifyCounterpartyRequiresRun(t *testing.T) {
+	db, _ := dryRun(t)
+	order := &models.Order{ID: "order-1", BuyerID: "buyer-1", FarmerID: "farmer-1"}
+	err := orderstate.NotifyCounterparty(db, order, orderstate.Actor{Role: orderstate.RoleFarmer}, "subject", "message")
+	if !errors.Is(err, orderstate.ErrNoOutbox) {
+		t.Errorf("NotifyCounterparty() error = %v, want %v", err, orderstate.ErrNoOutbox)
+	}
+}
+
+// The tests below need MySQL; see package dbtest.
+
+func TestDefaultTransitionAppliesAllowedMoves(t *testing.T) {
+	db := dbtest.Open(t)
+	recorder := recordNotifications(t)
+
+	for from, targets := range defaultRules {
+		for to, allowed := range targets {
+			for _, role := range allowed {
+				from, to, role := from, to, role
+				t.Run(fmt.Sprintf("%s->%s by %s", from, to, role), func(t *testing.T) {
+					order := createOrder(t, db, from)
+					// Cancelling an accepted order approves the buyer's request
+					order.CancellationRequested = to == orderstate.Cancelled && from == orderstate.Accepted
+					if err := db.Model(order).Update("cancellation_requested", order.CancellationRequested).Error; err != nil {
+						t.Fatalf("failed to set cancellation request: %v", err)
+					}
+					actor := orderstate.Actor{ID: actorID(order, role), Role: role}
+
+					err := orderstate.Run(db, func(tx *gorm.DB) error {
+						return orderstate.Default.Transition(tx, order, to, actor, "test")
+					})
+					if err != nil {
+						t.Fatalf("Transition() error = %v", err)
+					}
+
+					var saved models.Order
+					if err := db.First(&saved, "id = ?", order.ID).Error; err != nil {
+						t.Fatalf("failed to reload order: %v", err)
+					}
+					if saved.Status != string(to) {
+						t.Errorf("status = %s, want %s", saved.Status, to)
+					}
+
+					var events []models.OrderStatusEvent
+					if err := db.Where("order_id = ?", order.ID).Find(&events).Error; err != nil {
+						t.Fatalf("failed to load events: %v", err)
+					}
+					if len(events) != 1 || events[0].FromStatus != string(from) || events[0].ToStatus != string(to) ||
+						events[0].ActorRole != role || events[0].ActorID != actor.ID {
+						t.Errorf("events = %+v, want one %s -> %s by %s", events, from, to, role)
+					}
+
+					if got := recorder.wait(order.ID); got == 0 {
+						t.Error("no notification was sent after commit")
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestRunRollsBackFailingHooks(t *testing.T) {
+	db := dbtest.Open(t)
+	recorder := recordNotifications(t)
+
+	errHook := errors.New("hook failed")
+	fail := func(*gorm.DB, *models.Order, orderstate.Change) error { return errHook }
+	rule := orderstate.Allow(orderstate.Pending, orderstate.Accepted, orderstate.RoleFarmer)
+	states := []orderstate.State{orderstate.Pending, orderstate.Accepted}
+
+	tests := []struct {
+		name    string
+		machine *orderstate.Machine
+	}{
+		{"before hook", orderstate.New(states, rule.Before(fail)).WithPost(notifyBoth)},
+		{"after hook", orderstate.New(states, rule.After(fail)).WithPost(notifyBoth)},
+		{"machine post hook", orderstate.New(states, rule).WithPost(notifyBoth, fail)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := createOrder(t, db, orderstate.Pending)
+			actor := orderstate.Actor{ID: order.FarmerID, Role: orderstate.RoleFarmer}
+
+			err := orderstate.Run(db, func(tx *gorm.DB) error {
+				return tt.machine.Transition(tx, order, orderstate.Accepted, actor, "")
+			})
+			if !errors.Is(err, errHook) {
+				t.Fatalf("Run() error = %v, want %v", err, errHook)
+			}
+
+			var saved models.Order
+			if err := db.First(&saved, "id = ?", order.ID).Error; err != nil {
+				t.Fatalf("failed to reload order: %v", err)
+			}
+			if saved.Status != string(orderstate.Pending) {
+				t.Errorf("status = %s, want pending", saved.Status)
+			}
+
+			var events int64
+			if err := db.Model(&models.OrderStatusEvent{}).Where("order_id = ?", order.ID).Count(&events).Error; err != nil {
+				t.Fatalf("failed to count events: %v", err)
+			}
+			if events != 0 {
+				t.Errorf("%d status events were written, want none", events)
+			}
+
+			if got := recorder.wait(order.ID); got != 0 {
+				t.Errorf("%d notifications were sent for a rolled-back transition", got)
+			}
+		})
+	}
+}
+
+func notifyBoth(tx *gorm.DB, order *models.Order, _ orderstate.Change) error {
+	return orderstate.NotifyCounterparty(tx, order, orderstate.System, "subject", order.ID)
+}
+
+// dryRun returns a MySQL session that renders statements without running them,
+// and the list of statements it rendered
+func dryRun(t *testing.T) (*gorm.DB, *statementLog) {
+	t.Helper()
+	statements := &statementLog{}
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "test:test@tcp(127.0.0.1:3306)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: statements})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	return db, statements
+}
+
+// statementLog is a gorm logger that keeps the SQL of every statement
+type statementLog struct {
+	mu  sync.Mutex
+	sql []string
+}
+
+func (l *statementLog) LogMode(logger.LogLevel) logger.Interface      { return l }
+func (l *statementLog) Info(context.Context, string, ...interface{})  {}
+func (l *statementLog) Warn(context.Context, string, ...interface{})  {}
+func (l *statementLog) Error(context.Context, string, ...interface{}) {}
+
+func (l *statementLog) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sql = append(l.sql, sql)
+}
+
+func (l *statementLog) all() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.sql...)
+}
+
+// notificationRecorder counts notifications by message; the test notifications
+// carry the order ID somewhere in their message
+type notificationRecorder struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func recordNotifications(t *testing.T) *notificationRecorder {
+	r := &notificationRecorder{}
+	notify.SetDefault(r)
+	t.Cleanup(func() { notify.SetDefault(notify.LogNotifier{}) })
+	return r
+}
+
+func (r *notificationRecorder) Notify(_, _, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+// wait returns how many notifications mention orderID, giving Send's
+// background delivery a moment to finish
+func (r *notificationRecorder) wait(orderID string) int {
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for {
+		r.mu.Lock()
+		n := 0
+		for _, m := range r.messages {
+			if strings.Contains(m, orderID) {
+				n++
+			}
+		}
+		r.mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// createOrder stores a buyer, a farmer and an order between them in the given state
+func createOrder(t *testing.T, db *gorm.DB, status orderstate.State) *models.Order {
+	t.Helper()
+	users := make([]models.User, 2)
+	for i, role := range []string{authz.RoleBuyer, authz.RoleFarmer} {
+		users[i] = models.User{
+			Phone:        fmt.Sprintf("9%09d", rand.Int63n(1e9)),
+			Name:         "Test " + role,
+			PasswordHash: "x",
+			Role:         role,
+		}
+		if err := db.Create(&users[i]).Error; err != nil {
+			t.Fatalf("failed to create %s: %v", role, err)
+		}
+	}
+	order := &models.Order{
+		BuyerID:      users[0].ID,
+		FarmerID:     users[1].ID,
+		Status:       string(status),
+		DeliveryMode: "pickup",
+	}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+	return order
+}
+
+func actorID(order *models.Order, role string) string {
+	switch role {
+	case orderstate.RoleBuyer:
+		return order.BuyerID
+	case orderstate.RoleFarmer:
+		return order.FarmerID
+	}
+	return ""
+}
@@ -0,0 +1,233 @@
+package otp
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/sms"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Purposes an OTP can be issued for
+const (
+	PurposeVerifyPhone   = "verify_phone"
+	PurposePasswordReset = "password_reset"
+)
+
+const (
+	codeLength = 6
+	// maxAttempts is how many wrong guesses a single code tolerates
+	maxAttempts = 5
+	// maxSendsPerHour caps how many codes can be requested per phone and purpose
+	// in an hour, whether or not the phone is registered
+	maxSendsPerHour = 5
+)
+
+// ErrInvalidCode is returned when no active code matches
+var ErrInvalidCode = errors.New("invalid or expired code")
+
+// ErrTooManyAttempts is returned once a code has been guessed wrong too often
+var ErrTooManyAttempts = errors.New("too many incorrect attempts, request a new code")
+
+// RateLimitError is returned when a phone must wait before receiving another code
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many code requests, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+var (
+	sender         sms.Sender = sms.ConsoleSender{}
+	codeTTL                   = 5 * time.Minute
+	resendCooldown            = time.Minute
+)
+
+// Init configures the SMS sender and code timings
+func Init(cfg config.Config, s sms.Sender) {
+	sender = s
+	if cfg.OTPTTL > 0 {
+		codeTTL = cfg.OTPTTL
+	}
+	if cfg.OTPResendCooldown > 0 {
+		resendCooldown = cfg.OTPResendCooldown
+	}
+}
+
+// Issue generates a new code for phone and purpose, stores its hash and sends it
+// by SMS. Any earlier unused code for the same purpose stops working. It is for
+// phones known to belong to a user; public endpoints use Request.
+func Issue(db *gorm.DB, phone, purpose string) error {
+	return issue(db, phone, purpose, func(*gorm.DB, string) (bool, error) { return true, nil })
+}
+
+// Request issues a code as Issue does when phone belongs to an active user and
+// otherwise sends nothing. The rate limit is applied and counted before the
+// lookup either way, so the result does not reveal whether the phone is registered.
+func Request(db *gorm.DB, phone, purpose string) error {
+	return issue(db, phone, purpose, activeUser)
+}
+
+// issue counts the request against the rate limit and, if eligible approves the
+// phone, replaces its code, all in one transaction holding the phone's
+// throttle row. The SMS is sent once the transaction has committed.
+func issue(db *gorm.DB, phone, purpose string, eligible func(tx *gorm.DB, phone string) (bool, error)) error {
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	send := false
+	err = db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := throttle(tx, phone, purpose, now); err != nil {
+			return err
+		}
+
+		ok, err := eligible(tx, phone)
+		if err != nil || !ok {
+			return err
+		}
+
+		// Retire earlier codes so only the newest one can be used
+		if err := tx.Model(&models.OTPCode{}).
+			Where("phone = ? AND purpose = ? AND consumed_at IS NULL AND expires_at > ?", phone, purpose, now).
+			Update("expires_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.OTPCode{
+			Phone:     phone,
+			Purpose:   purpose,
+			CodeHash:  string(hash),
+			ExpiresAt: now.Add(codeTTL),
+		}).Error; err != nil {
+			return err
+		}
+		send = true
+		return nil
+	})
+	if err != nil || !send {
+		return err
+	}
+
+	message := fmt.Sprintf("Your verification code is %s. It expires in %d minutes. Do not share it with anyone.", code, int(codeTTL.Minutes()))
+	return sender.Send(phone, message)
+}
+
+// throttle locks the request counter for phone and purpose and counts one more
+// request, or returns a RateLimitError when the phone must wait
+func throttle(tx *gorm.DB, phone, purpose string, now time.Time) error {
+	// Create the counter on first use so there is always a row to lock
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.OTPThrottle{
+		Phone:           phone,
+		Purpose:         purpose,
+		WindowStartedAt: now,
+		LastRequestAt:   now,
+	}).Error; err != nil {
+		return err
+	}
+
+	var t models.OTPThrottle
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("phone = ? AND purpose = ?", phone, purpose).
+		First(&t).Error; err != nil {
+		return err
+	}
+
+	if t.Requests > 0 {
+		if wait := t.LastRequestAt.Add(resendCooldown).Sub(now); wait > 0 {
+			return &RateLimitError{RetryAfter: wait}
+		}
+	}
+	if now.Sub(t.WindowStartedAt) >= time.Hour {
+		t.WindowStartedAt, t.Requests = now, 0
+	}
+	if t.Requests >= maxSendsPerHour {
+		return &RateLimitError{RetryAfter: t.WindowStartedAt.Add(time.Hour).Sub(now)}
+	}
+
+	return tx.Model(&t).Updates(map[string]interface{}{
+		"window_started_at": t.WindowStartedAt,
+		"requests":          t.Requests + 1,
+		"last_request_at":   now,
+	}).Error
+}
+
+// activeUser reports whether phone belongs to an active account
+func activeUser(tx *gorm.DB, phone string) (bool, error) {
+	var user models.User
+	if err := tx.Select("is_active").Where("phone = ?", phone).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.IsActive, nil
+}
+
+// Verify checks code against the newest active code for phone and purpose and
+// consumes it on success. Wrong guesses count against the code's attempt limit.
+func Verify(db *gorm.DB, phone, purpose, code string) error {
+	// A wrong guess must still commit its attempt, so it is reported through
+	// result rather than by failing the transaction
+	var result error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var otp models.OTPCode
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("phone = ? AND purpose = ? AND consumed_at IS NULL AND expires_at > ?", phone, purpose, time.Now()).
+			Order("created_at DESC").
+			First(&otp).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidCode
+			}
+			return err
+		}
+
+		if otp.Attempts >= maxAttempts {
+			return ErrTooManyAttempts
+		}
+
+		if bcrypt.CompareHashAndPassword([]byte(otp.CodeHash), []byte(code)) != nil {
+			if err := tx.Model(&otp).Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+				return err
+			}
+			result = ErrInvalidCode
+			if otp.Attempts+1 >= maxAttempts {
+				result = ErrTooManyAttempts
+			}
+			return nil
+		}
+
+		return tx.Model(&otp).Update("consumed_at", time.Now()).Error
+	})
+	if err != nil {
+		return err
+	}
+	return result
+}
+
+// generateCode returns a uniformly random numeric code
+func generateCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < codeLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeLength, n), nil
+}
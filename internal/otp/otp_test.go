@@ -0,0 +1,102 @@
+package otp
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"farmer-to-buyer-portal/internal/dbtest"
+	"farmer-to-buyer-portal/internal/models"
+)
+
+// recordingSender remembers the phones it was asked to text
+type recordingSender struct {
+	mu     sync.Mutex
+	phones []string
+}
+
+func (s *recordingSender) Send(phone, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.phones = append(s.phones, phone)
+	return nil
+}
+
+// useSender replaces the SMS sender for the duration of the test
+func useSender(t *testing.T) *recordingSender {
+	previous := sender
+	s := &recordingSender{}
+	sender = s
+	t.Cleanup(func() { sender = previous })
+	return s
+}
+
+func randomPhone() string {
+	return fmt.Sprintf("9%09d", rand.Int63n(1e9))
+}
+
+func TestRequestLimitsUnknownPhonesLikeRegisteredOnes(t *testing.T) {
+	db := dbtest.Open(t)
+	s := useSender(t)
+
+	registered := models.User{Phone: randomPhone(), Name: "Test", PasswordHash: "x", Role: "buyer", IsActive: true}
+	if err := db.Create(&registered).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	unknown := randomPhone()
+
+	for _, phone := range []string{registered.Phone, unknown} {
+		if err := Request(db, phone, PurposeVerifyPhone); err != nil {
+			t.Fatalf("first Request(%s) error = %v", phone, err)
+		}
+		var rateLimit *RateLimitError
+		if err := Request(db, phone, PurposeVerifyPhone); !errors.As(err, &rateLimit) {
+			t.Errorf("second Request(%s) error = %v, want a RateLimitError", phone, err)
+		}
+	}
+
+	if len(s.phones) != 1 || s.phones[0] != registered.Phone {
+		t.Errorf("texted %v, want only %s", s.phones, registered.Phone)
+	}
+	var codes int64
+	if err := db.Model(&models.OTPCode{}).Where("phone = ?", unknown).Count(&codes).Error; err != nil {
+		t.Fatalf("failed to count codes: %v", err)
+	}
+	if codes != 0 {
+		t.Errorf("stored %d codes for an unregistered phone", codes)
+	}
+}
+
+func TestConcurrentRequestsCountOnce(t *testing.T) {
+	db := dbtest.Open(t)
+	useSender(t)
+
+	phone := randomPhone()
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Request(db, phone, PurposePasswordReset)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	accepted := 0
+	for err := range errs {
+		var rateLimit *RateLimitError
+		switch {
+		case err == nil:
+			accepted++
+		case !errors.As(err, &rateLimit):
+			t.Errorf("Request() error = %v", err)
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("%d concurrent requests passed the cooldown, want 1", accepted)
+	}
+}
@@ -0,0 +1,234 @@
+// Package pagination implements keyset (cursor) pagination over sorted queries.
+//
+// Every ordering sorts by one key and then by id, so rows with equal keys keep
+// a fixed order. A cursor records the sort, key and id of the last row served;
+// the next page continues strictly after that row, so rows inserted meanwhile
+// never shift or repeat entries the way offset pagination does.
+//
+// Product and order listings were bare JSON arrays before they were paginated.
+// Requests that send none of the limit, cursor and sort parameters still get
+// that shape (see Requested), so existing clients keep working.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultLimit is the page size when the limit parameter is omitted
+	DefaultLimit = 20
+	// MaxLimit caps the page size a client may request
+	MaxLimit = 100
+)
+
+// Errors returned while parsing request parameters
+var (
+	ErrInvalidLimit  = fmt.Errorf("limit must be an integer between 1 and %d", MaxLimit)
+	ErrInvalidCursor = errors.New("cursor is invalid or belongs to a different sort order")
+)
+
+// KeyKind is the type of a sort key, needed to decode it from a cursor
+type KeyKind int
+
+// Sort key kinds
+const (
+	KindInt KeyKind = iota
+	KindFloat
+	KindTime
+)
+
+// Sort is an ordering clients can ask for by name. Column must be non-null for
+// every row the query returns.
+type Sort struct {
+	Name   string        // value of the sort parameter
+	Column string        // SQL column or expression, qualified if the query joins
+	Args   []interface{} // values for the placeholders in Column
+	Kind   KeyKind
+	Desc   bool
+	// IDColumn is the tie-breaker column; defaults to "id"
+	IDColumn string
+}
+
+// Request is a parsed page request
+type Request struct {
+	Limit int
+	Sort  Sort
+	after *cursor
+}
+
+// Page describes the page returned to the client
+type Page struct {
+	Sort       string `json:"sort"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// cursor is the decoded form of an opaque cursor string
+type cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+// Requested reports whether any of the limit, cursor and sort parameters was
+// given. Product and order listings answer requests without them with a bare
+// array ordered by the default sort, the shape they had before pagination.
+func Requested(limitParam, cursorParam, sortParam string) bool {
+	return limitParam != "" || cursorParam != "" || sortParam != ""
+}
+
+// Parse reads the limit, cursor and sort parameters. An empty sort selects
+// sorts[0]; an unknown sort returns an error naming the accepted values.
+func Parse(limitParam, cursorParam, sortParam string, sorts []Sort) (Request, error) {
+	req := Request{Limit: DefaultLimit, Sort: sorts[0]}
+
+	if sortParam != "" {
+		found := false
+		for _, s := range sorts {
+			if s.Name == sortParam {
+				req.Sort, found = s, true
+				break
+			}
+		}
+		if !found {
+			names := make([]string, len(sorts))
+			for i, s := range sorts {
+				names[i] = s.Name
+			}
+			return req, fmt.Errorf("sort must be one of %v", names)
+		}
+	}
+
+	if limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return req, ErrInvalidLimit
+		}
+		req.Limit = limit
+	}
+
+	if cursorParam != "" {
+		after, err := decode(cursorParam)
+		if err != nil || after.Sort != req.Sort.Name {
+			return req, ErrInvalidCursor
+		}
+		if _, err := parseKey(after.Key, req.Sort.Kind); err != nil {
+			return req, ErrInvalidCursor
+		}
+		req.after = &after
+	}
+
+	return req, nil
+}
+
+// Apply orders query by the requested sort, skips past the cursor and fetches
+// one row more than the limit so Page can tell whether another page exists.
+// Count the total before calling Apply.
+func (r Request) Apply(query *gorm.DB) *gorm.DB {
+	comparison := ">"
+	if r.Sort.Desc {
+		comparison = "<"
+	}
+
+	if r.after != nil {
+		key, _ := parseKey(r.after.Key, r.Sort.Kind)
+		var vars []interface{}
+		vars = append(append(vars, r.Sort.Args...), key)
+		vars = append(append(vars, r.Sort.Args...), key, r.after.ID)
+		query = query.Where(
+			fmt.Sprintf("%[1]s %[3]s ? OR (%[1]s = ? AND %[2]s %[3]s ?)", r.Sort.Column, r.idColumn(), comparison),
+			vars...,
+		)
+	}
+
+	return r.Order(query).Limit(r.Limit + 1)
+}
+
+// Order orders query by the requested sort without skipping or limiting rows
+func (r Request) Order(query *gorm.DB) *gorm.DB {
+	direction := "ASC"
+	if r.Sort.Desc {
+		direction = "DESC"
+	}
+	return query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                fmt.Sprintf("%[1]s %[3]s, %[2]s %[3]s", r.Sort.Column, r.idColumn(), direction),
+		Vars:               r.Sort.Args,
+		WithoutParentheses: true,
+	}})
+}
+
+// Page builds the page metadata for fetched rows, as returned by a query
+// prepared with Apply, and the number of rows to return. keyAt returns the sort
+// key and id of the row at index i, the key typed as int64, float64 or time.Time.
+func (r Request) Page(fetched int, total int64, keyAt func(i int) (interface{}, string)) (Page, int) {
+	page := Page{Sort: r.Sort.Name, Limit: r.Limit, Total: total}
+	if fetched <= r.Limit {
+		return page, fetched
+	}
+
+	key, id := keyAt(r.Limit - 1)
+	page.HasMore = true
+	page.NextCursor = encode(cursor{Sort: r.Sort.Name, Key: formatKey(key), ID: id})
+	return page, r.Limit
+}
+
+func (r Request) idColumn() string {
+	if r.Sort.IDColumn != "" {
+		return r.Sort.IDColumn
+	}
+	return "id"
+}
+
+func encode(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decode(s string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(raw, &c)
+	return c, err
+}
+
+// formatKey writes a sort key losslessly as text. Keys must be int64, float64
+// or time.Time, matching the sort's KeyKind.
+func formatKey(key interface{}) string {
+	switch k := key.(type) {
+	case int64:
+		return strconv.FormatInt(k, 10)
+	case float64:
+		return strconv.FormatFloat(k, 'g', -1, 64)
+	case time.Time:
+		return k.UTC().Format(time.RFC3339Nano)
+	default:
+		panic(fmt.Sprintf("pagination: unsupported sort key type %T", key))
+	}
+}
+
+// parseKey reads a key written by formatKey
+func parseKey(s string, kind KeyKind) (interface{}, error) {
+	switch kind {
+	case KindInt:
+		return strconv.ParseInt(s, 10, 64)
+	case KindFloat:
+		return strconv.ParseFloat(s, 64)
+	case KindTime:
+		return time.Parse(time.RFC3339Nano, s)
+	default:
+		return nil, fmt.Errorf("unknown key kind %d", kind)
+	}
+}
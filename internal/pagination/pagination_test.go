@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testSorts = []Sort{
+	{Name: "newest", Column: "created_at", Kind: KindTime, Desc: true},
+	{Name: "price", Column: "price", Kind: KindInt},
+}
+
+func TestRequested(t *testing.T) {
+	if Requested("", "", "") {
+		t.Error("Requested() with no parameters = true, want false")
+	}
+	for _, params := range [][3]string{{"10", "", ""}, {"", "abc", ""}, {"", "", "price"}} {
+		if !Requested(params[0], params[1], params[2]) {
+			t.Errorf("Requested(%q) = false, want true", params)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	req, err := Parse("", "", "", testSorts)
+	if err != nil || req.Limit != DefaultLimit || req.Sort.Name != "newest" {
+		t.Errorf("Parse() = %+v, %v, want the default limit and first sort", req, err)
+	}
+
+	if _, err := Parse("", "", "cheapest", testSorts); err == nil {
+		t.Error("Parse() accepted an unknown sort")
+	}
+	for _, limit := range []string{"0", "-1", "abc", "101"} {
+		if _, err := Parse(limit, "", "", testSorts); !errors.Is(err, ErrInvalidLimit) {
+			t.Errorf("Parse(limit=%s) error = %v, want ErrInvalidLimit", limit, err)
+		}
+	}
+	if _, err := Parse("", "not-a-cursor", "", testSorts); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Parse(bad cursor) error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	req, _ := Parse("2", "", "", testSorts)
+	created := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.UTC)
+
+	page, n := req.Page(3, 10, func(i int) (interface{}, string) { return created, "id-2" })
+	if n != 2 || !page.HasMore || page.NextCursor == "" || page.Total != 10 {
+		t.Fatalf("Page() = %+v, %d, want 2 rows and a next cursor", page, n)
+	}
+
+	next, err := Parse("2", page.NextCursor, "", testSorts)
+	if err != nil {
+		t.Fatalf("Parse(next cursor) error = %v", err)
+	}
+	key, _ := parseKey(next.after.Key, KindTime)
+	if !key.(time.Time).Equal(created) || next.after.ID != "id-2" {
+		t.Errorf("cursor = %+v, want key %s and id id-2", next.after, created)
+	}
+
+	// A cursor only continues the sort it was issued for
+	if _, err := Parse("2", page.NextCursor, "price", testSorts); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Parse(cursor, other sort) error = %v, want ErrInvalidCursor", err)
+	}
+
+	if page, n := req.Page(2, 2, nil); n != 2 || page.HasMore || page.NextCursor != "" {
+		t.Errorf("Page() on the last page = %+v, %d, want no next cursor", page, n)
+	}
+}
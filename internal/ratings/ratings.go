@@ -0,0 +1,55 @@
+package ratings
+
+import (
+	"math"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RefreshFarmerStats recomputes a farmer's aggregate rating from their reviews and
+// their order count from delivered orders. It should run in the same transaction
+// as the change that affects either number. Farmers without a profile are skipped.
+//
+// The profile row is locked before the aggregates are read, so concurrent
+// deliveries and reviews for the same farmer recompute one after another and
+// each sees the changes committed before it. That holds as long as the caller
+// has made no unlocked read earlier in the transaction, which would pin an
+// older snapshot.
+func RefreshFarmerStats(tx *gorm.DB, farmerID string) error {
+	var profile models.FarmerProfile
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("farmer_id = ?", farmerID).
+		Limit(1).
+		Find(&profile).Error; err != nil {
+		return err
+	}
+	if profile.FarmerID == "" {
+		return nil
+	}
+
+	var stats struct {
+		Average float64
+	}
+	if err := tx.Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average").
+		Where("farmer_id = ?", farmerID).
+		Scan(&stats).Error; err != nil {
+		return err
+	}
+
+	var delivered int64
+	if err := tx.Model(&models.Order{}).
+		Where("farmer_id = ? AND status = ?", farmerID, "delivered").
+		Count(&delivered).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&profile).
+		Updates(map[string]interface{}{
+			"rating":       math.Round(stats.Average*100) / 100,
+			"total_orders": delivered,
+		}).Error
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/handlers"
+	"farmer-to-buyer-portal/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAdminRoutes registers admin moderation routes
+func SetupAdminRoutes(rg *gin.RouterGroup) {
+	admin := rg.Group("/admin")
+	admin.Use(middleware.AuthRequired(), middleware.RequireRole(authz.RoleAdmin)) // Admins only
+	{
+		users := admin.Group("/users", middleware.RequirePermission(authz.UsersManage))
+		users.GET("", handlers.AdminListUsers)
+		users.GET("/:id", handlers.AdminGetUser)
+		users.PUT("/:id/status", handlers.AdminUpdateUserStatus)
+		users.POST("/:id/force-password-reset", handlers.AdminForcePasswordReset)
+		users.GET("/:id/products", handlers.AdminGetUserProducts)
+		users.GET("/:id/orders", handlers.AdminGetUserOrders)
+
+		products := admin.Group("/products", middleware.RequirePermission(authz.ProductsModerate))
+		products.GET("/moderation", handlers.AdminGetModerationQueue)
+		products.POST("/:id/moderation", handlers.AdminModerateProduct)
+
+		catalog := admin.Group("/catalog", middleware.RequirePermission(authz.CatalogManage))
+		catalog.POST("/categories", handlers.AdminCreateCropCategory)
+		catalog.PUT("/categories/:id", handlers.AdminUpdateCropCategory)
+		catalog.DELETE("/categories/:id", handlers.AdminDeleteCropCategory)
+		catalog.POST("/crops", handlers.AdminCreateCrop)
+		catalog.PUT("/crops/:id", handlers.AdminUpdateCrop)
+		catalog.DELETE("/crops/:id", handlers.AdminDeleteCrop)
+		catalog.POST("/crops/:id/varieties", handlers.AdminAddCropVariety)
+		catalog.DELETE("/crops/:id/varieties/:variety_id", handlers.AdminDeleteCropVariety)
+		catalog.POST("/crops/:id/synonyms", handlers.AdminAddCropSynonym)
+		catalog.DELETE("/crops/:id/synonyms/:synonym_id", handlers.AdminDeleteCropSynonym)
+
+		admin.GET("/audit-logs", middleware.RequirePermission(authz.AuditLogView), handlers.AdminGetAuditLogs)
+	}
+}
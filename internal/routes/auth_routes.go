@@ -13,6 +13,15 @@ func SetupAuthRoutes(rg *gin.RouterGroup) {
 	{
 		auth.POST("/register", handlers.Register)
 		auth.POST("/login", handlers.Login)
+		auth.POST("/otp/request", handlers.RequestOTP)
+		auth.POST("/otp/verify", handlers.VerifyOTP)
+		auth.POST("/password/forgot", handlers.ForgotPassword)
+		auth.POST("/password/reset", handlers.ResetPassword)
+		auth.POST("/refresh", handlers.Refresh)
+		auth.POST("/logout", middleware.AuthRequired(), handlers.Logout)
+		auth.GET("/sessions", middleware.AuthRequired(), handlers.GetSessions)
+		auth.DELETE("/sessions", middleware.AuthRequired(), handlers.RevokeAllSessions)
+		auth.DELETE("/sessions/:id", middleware.AuthRequired(), handlers.RevokeSession)
 	}
 
 	// Protected route
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"farmer-to-buyer-portal/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCatalogRoutes registers the public crop catalog routes; admins manage
+// the catalog under /admin/catalog
+func SetupCatalogRoutes(rg *gin.RouterGroup) {
+	catalog := rg.Group("/catalog")
+	{
+		catalog.GET("/categories", handlers.GetCropCategories)
+		catalog.GET("/crops", handlers.GetCrops)
+		catalog.GET("/crops/:id", handlers.GetCrop)
+		catalog.GET("/languages", handlers.GetCatalogLanguages)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"farmer-to-buyer-portal/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupFarmerRoutes registers public farmer storefront routes
+func SetupFarmerRoutes(rg *gin.RouterGroup) {
+	farmers := rg.Group("/farmers")
+	{
+		farmers.GET("", handlers.GetFarmers)
+		farmers.GET("/:id", handlers.GetFarmer)
+		farmers.GET("/:id/reviews", handlers.GetFarmerReviews)
+	}
+}
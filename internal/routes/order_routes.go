@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"farmer-to-buyer-portal/internal/authz"
 	"farmer-to-buyer-portal/internal/handlers"
 	"farmer-to-buyer-portal/internal/middleware"
 
@@ -12,10 +13,19 @@ func SetupOrderRoutes(rg *gin.RouterGroup) {
 	orders := rg.Group("/orders")
 	orders.Use(middleware.AuthRequired()) // All order routes require authentication
 	{
-		orders.POST("", handlers.CreateOrder)
-		orders.GET("/:id", handlers.GetOrder)
-		orders.GET("/buyer/me", handlers.GetBuyerOrders)
-		orders.GET("/farmer/me", handlers.GetFarmerOrders)
-		orders.PUT("/:id/status", handlers.UpdateOrderStatus)
+		// Buyer routes
+		orders.POST("", middleware.RequirePermission(authz.OrdersPlace), handlers.CreateOrder)
+		orders.POST("/checkout", middleware.RequirePermission(authz.OrdersPlace), handlers.Checkout)
+		orders.GET("/checkout/:checkout_id", middleware.RequirePermission(authz.OrdersPlace), handlers.GetCheckout)
+		orders.GET("/buyer/me", middleware.RequirePermission(authz.OrdersPlace), handlers.GetBuyerOrders)
+		orders.POST("/:id/cancel", middleware.RequirePermission(authz.OrdersCancel), handlers.CancelOrder)
+		orders.POST("/:id/review", middleware.RequirePermission(authz.ReviewsWrite), handlers.CreateReview)
+
+		// Farmer routes
+		orders.GET("/farmer/me", middleware.RequirePermission(authz.OrdersFulfil), handlers.GetFarmerOrders)
+		orders.PUT("/:id/status", middleware.RequirePermission(authz.OrdersFulfil), handlers.UpdateOrderStatus)
+		orders.PUT("/:id/cancellation", middleware.RequirePermission(authz.OrdersFulfil), handlers.ResolveCancellation)
+
+		orders.GET("/:id", middleware.RequirePermission(authz.OrdersView), handlers.GetOrder)
 	}
 }
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"farmer-to-buyer-portal/internal/authz"
 	"farmer-to-buyer-portal/internal/handlers"
 	"farmer-to-buyer-portal/internal/middleware"
 
@@ -13,12 +14,15 @@ func SetupProductRoutes(rg *gin.RouterGroup) {
 	{
 		// Public routes
 		products.GET("", handlers.GetProducts)
+		products.GET("/search", handlers.SearchProducts)
 		products.GET("/:id", handlers.GetProduct)
+		products.GET("/:id/reviews", handlers.GetProductReviews)
 
 		// Protected routes (farmer only)
-		products.POST("", middleware.AuthRequired(), handlers.CreateProduct)
-		products.GET("/me", middleware.AuthRequired(), handlers.GetMyProducts)
-		products.PUT("/:id", middleware.AuthRequired(), handlers.UpdateProduct)
-		products.DELETE("/:id", middleware.AuthRequired(), handlers.DeleteProduct)
+		manage := products.Group("", middleware.AuthRequired(), middleware.RequirePermission(authz.ProductsManage))
+		manage.POST("", handlers.CreateProduct)
+		manage.GET("/me", handlers.GetMyProducts)
+		manage.PUT("/:id", handlers.UpdateProduct)
+		manage.DELETE("/:id", handlers.DeleteProduct)
 	}
 }
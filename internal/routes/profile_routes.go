@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/handlers"
+	"farmer-to-buyer-portal/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupProfileRoutes registers farmer and buyer profile routes
+func SetupProfileRoutes(rg *gin.RouterGroup) {
+	profile := rg.Group("/profile")
+	profile.Use(middleware.AuthRequired()) // All profile routes require authentication
+	{
+		profile.GET("/farmer", middleware.RequirePermission(authz.FarmerProfileManage), handlers.GetFarmerProfile)
+		profile.PUT("/farmer", middleware.RequirePermission(authz.FarmerProfileManage), handlers.UpdateFarmerProfile)
+		profile.GET("/buyer", middleware.RequirePermission(authz.BuyerProfileManage), handlers.GetBuyerProfile)
+		profile.PUT("/buyer", middleware.RequirePermission(authz.BuyerProfileManage), handlers.UpdateBuyerProfile)
+	}
+}
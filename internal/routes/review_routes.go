@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"farmer-to-buyer-portal/internal/authz"
+	"farmer-to-buyer-portal/internal/handlers"
+	"farmer-to-buyer-portal/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupReviewRoutes registers review routes
+func SetupReviewRoutes(rg *gin.RouterGroup) {
+	reviews := rg.Group("/reviews")
+	reviews.Use(middleware.AuthRequired()) // All review routes require authentication
+	{
+		reviews.POST("/:id/reply", middleware.RequirePermission(authz.ReviewsReply), handlers.ReplyToReview)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"farmer-to-buyer-portal/internal/handlers"
+	"farmer-to-buyer-portal/internal/search"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -11,7 +12,7 @@ import (
 )
 
 // SetupRouter builds the Gin engine with middleware and routes.
-func SetupRouter(db *gorm.DB) *gin.Engine {
+func SetupRouter(db *gorm.DB, searcher search.Searcher) *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
@@ -24,18 +25,28 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// Make DB accessible in handlers via context.
+	// Make DB and search index accessible in handlers via context.
 	router.Use(func(c *gin.Context) {
 		c.Set("db", db)
+		c.Set("search", searcher)
 		c.Next()
 	})
 
+	// Public keys for services that verify our access tokens
+	router.GET("/.well-known/jwks.json", handlers.JWKS)
+
 	v1 := router.Group("/api/v1")
 	{
 		v1.GET("/health", handlers.Health)
+		v1.GET("/units", handlers.GetUnits)
 		SetupAuthRoutes(v1)
 		SetupProductRoutes(v1)
+		SetupCatalogRoutes(v1)
 		SetupOrderRoutes(v1)
+		SetupProfileRoutes(v1)
+		SetupFarmerRoutes(v1)
+		SetupReviewRoutes(v1)
+		SetupAdminRoutes(v1)
 	}
 
 	return router
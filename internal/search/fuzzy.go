@@ -0,0 +1,120 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxEdits is how many typos a word of n letters may contain and still match.
+// Short words must be exact: one edit turns most of them into another word.
+func maxEdits(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// levenshtein returns the edit distance between a and b, counting runes,
+// or limit+1 as soon as the distance is known to exceed limit
+func levenshtein(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// vocabulary is the set of indexed words queries are corrected against
+type vocabulary map[string]struct{}
+
+// add records every word of text
+func (v vocabulary) add(text string) {
+	for _, word := range Tokenize(text) {
+		v[word] = struct{}{}
+	}
+}
+
+// addListing records the words a listing can be found by: its crop name,
+// description and the keywords from other tables (see Document.keywords).
+// Every Searcher builds its vocabulary through here so they correct alike.
+func (v vocabulary) addListing(cropName, description, keywords string) {
+	v.add(cropName)
+	v.add(description)
+	v.add(keywords)
+}
+
+// correct replaces each word of text that is not in the vocabulary with the
+// closest word that is, within maxEdits. It returns the corrected words and
+// whether any word changed.
+func (v vocabulary) correct(text string) ([]string, bool) {
+	words := Tokenize(text)
+	changed := false
+	for i, word := range words {
+		if _, ok := v[word]; ok {
+			continue
+		}
+		limit := maxEdits(utf8.RuneCountInString(word))
+		if limit == 0 {
+			continue
+		}
+
+		best, bestDistance := "", limit+1
+		for candidate := range v {
+			d := levenshtein(word, candidate, limit)
+			// Ties go to the alphabetically first word so results are repeatable
+			if d < bestDistance || (d == bestDistance && d <= limit && candidate < best) {
+				best, bestDistance = candidate, d
+			}
+		}
+		if best != "" {
+			words[i] = best
+			changed = true
+		}
+	}
+	return words, changed
+}
+
+// sortHits orders hits by descending score, then by product ID for a stable order
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ProductID < hits[j].ProductID
+	})
+}
+
+// correctedText joins corrected words back into a query, or returns "" when unchanged
+func correctedText(words []string, changed bool) string {
+	if !changed {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"context"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// reindexBatchSize bounds how many products Reindex loads at once
+const reindexBatchSize = 500
+
+// Reindex rebuilds the documents of the products matching conds, e.g.
+// Reindex(ctx, db, s, "farmer_id = ?", farmerID), or of every product when
+// conds is empty
+func Reindex(ctx context.Context, db *gorm.DB, s Searcher, conds ...interface{}) error {
+	query := db.WithContext(ctx)
+	if len(conds) > 0 {
+		query = query.Where(conds[0], conds[1:]...)
+	}
+
+	var products []models.Product
+	result := query.FindInBatches(&products, reindexBatchSize, func(tx *gorm.DB, batch int) error {
+		docs, err := documents(tx, products)
+		if err != nil {
+			return err
+		}
+		return s.Index(ctx, docs...)
+	})
+	return result.Error
+}
+
+// documents builds the search documents of products, loading the farm names,
+// crops and varieties they refer to in one query each
+func documents(db *gorm.DB, products []models.Product) ([]Document, error) {
+	db = db.Session(&gorm.Session{NewDB: true})
+
+	farmerIDs := make([]string, 0, len(products))
+	var cropIDs, varietyIDs []string
+	for _, p := range products {
+		farmerIDs = append(farmerIDs, p.FarmerID)
+		if p.CropID != nil {
+			cropIDs = append(cropIDs, *p.CropID)
+		}
+		if p.VarietyID != nil {
+			varietyIDs = append(varietyIDs, *p.VarietyID)
+		}
+	}
+
+	var profiles []models.FarmerProfile
+	if err := db.Select("farmer_id", "farm_name").Where("farmer_id IN ?", farmerIDs).Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	farmNames := make(map[string]string, len(profiles))
+	for _, p := range profiles {
+		farmNames[p.FarmerID] = p.FarmName
+	}
+
+	crops := make(map[string]models.Crop)
+	if len(cropIDs) > 0 {
+		var rows []models.Crop
+		if err := db.Preload("Category").Preload("Synonyms").Where("id IN ?", cropIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, crop := range rows {
+			crops[crop.ID] = crop
+		}
+	}
+
+	varieties := make(map[string]string)
+	if len(varietyIDs) > 0 {
+		var rows []models.CropVariety
+		if err := db.Where("id IN ?", varietyIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, v := range rows {
+			varieties[v.ID] = v.Name
+		}
+	}
+
+	docs := make([]Document, len(products))
+	for i, p := range products {
+		doc := Document{
+			ProductID:   p.ID,
+			CropName:    p.CropName,
+			Description: p.Description,
+			FarmName:    farmNames[p.FarmerID],
+			State:       p.State,
+			Price:       p.PricePerUnit,
+			Active:      p.Status == "active",
+		}
+		if p.VarietyID != nil {
+			doc.Variety = varieties[*p.VarietyID]
+		}
+		if p.CropID != nil {
+			if crop, ok := crops[*p.CropID]; ok {
+				doc.Category = crop.Category.Slug
+				doc.Keywords = append(doc.Keywords, crop.Name, crop.Category.Name)
+				for _, synonym := range crop.Synonyms {
+					doc.Keywords = append(doc.Keywords, synonym.Term)
+				}
+			}
+		}
+		docs[i] = doc
+	}
+	return docs, nil
+}
@@ -0,0 +1,164 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Field weights: a match on the crop's own name outranks one on the farm name
+const (
+	weightCropName    = 3.0
+	weightKeywords    = 2.0
+	weightVariety     = 2.0
+	weightFarmName    = 1.0
+	weightDescription = 1.0
+
+	// prefixFactor scales the weight of a word that only starts with the query word
+	prefixFactor = 0.5
+)
+
+// Memory is a Searcher that keeps documents in process. Documents are only as
+// current as the last Index call; nothing is read back from the database.
+type Memory struct {
+	mu   sync.RWMutex
+	docs map[string]Document
+}
+
+// NewMemory returns an empty in-memory searcher
+func NewMemory() *Memory {
+	return &Memory{docs: make(map[string]Document)}
+}
+
+// Index implements Searcher
+func (m *Memory) Index(ctx context.Context, docs ...Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, doc := range docs {
+		m.docs[doc.ProductID] = doc
+	}
+	return nil
+}
+
+// Remove implements Searcher
+func (m *Memory) Remove(ctx context.Context, productIDs ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range productIDs {
+		delete(m.docs, id)
+	}
+	return nil
+}
+
+// Search implements Searcher
+func (m *Memory) Search(ctx context.Context, q Query) (Result, error) {
+	var band PriceBand
+	if q.PriceBand != "" {
+		var err error
+		if band, err = priceBand(q.PriceBand); err != nil {
+			return Result{}, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vocab := make(vocabulary)
+	for _, doc := range m.docs {
+		if doc.Active {
+			vocab.addListing(doc.CropName, doc.Description, doc.keywords())
+		}
+	}
+	words, changed := vocab.correct(q.Text)
+
+	// Score every active document, then apply the filters per facet
+	type match struct {
+		doc   Document
+		score float64
+	}
+	var matches []match
+	for _, doc := range m.docs {
+		if !doc.Active {
+			continue
+		}
+		if score := scoreDocument(doc, words); score > 0 {
+			matches = append(matches, match{doc: doc, score: score})
+		}
+	}
+
+	inState := func(d Document) bool { return q.State == "" || strings.EqualFold(d.State, q.State) }
+	inCategory := func(d Document) bool { return q.Category == "" || d.Category == q.Category }
+	inBand := func(d Document) bool { return q.PriceBand == "" || bandOf(d.Price) == band.Name }
+
+	result := Result{CorrectedText: correctedText(words, changed)}
+	states, categories, bands := map[string]int{}, map[string]int{}, map[string]int{}
+	for _, mt := range matches {
+		d := mt.doc
+		if inCategory(d) && inBand(d) {
+			states[d.State]++
+		}
+		if inState(d) && inBand(d) && d.Category != "" {
+			categories[d.Category]++
+		}
+		if inState(d) && inCategory(d) {
+			bands[bandOf(d.Price)]++
+		}
+		if inState(d) && inCategory(d) && inBand(d) {
+			result.Hits = append(result.Hits, Hit{ProductID: d.ProductID, Score: mt.score})
+		}
+	}
+	result.Facets = Facets{
+		State:     facetCounts(states),
+		Category:  facetCounts(categories),
+		PriceBand: bandCounts(bands),
+	}
+
+	sortHits(result.Hits)
+	result.Total = len(result.Hits)
+	result.Hits = window(result.Hits, q.Offset, q.Limit)
+	return result, nil
+}
+
+// scoreDocument sums, for every query word, the weight of the best field it
+// matches exactly or as a prefix
+func scoreDocument(doc Document, words []string) float64 {
+	fields := []struct {
+		words  []string
+		weight float64
+	}{
+		{Tokenize(doc.CropName), weightCropName},
+		{Tokenize(strings.Join(doc.Keywords, " ")), weightKeywords},
+		{Tokenize(doc.Variety), weightVariety},
+		{Tokenize(doc.FarmName), weightFarmName},
+		{Tokenize(doc.Description), weightDescription},
+	}
+
+	var score float64
+	for _, word := range words {
+		best := 0.0
+		for _, field := range fields {
+			for _, w := range field.words {
+				switch {
+				case w == word:
+					best = max(best, field.weight)
+				case len(word) >= 3 && strings.HasPrefix(w, word):
+					best = max(best, field.weight*prefixFactor)
+				}
+			}
+		}
+		score += best
+	}
+	return score
+}
+
+// window returns the hits from offset, at most limit of them; a zero limit returns all
+func window(hits []Hit, offset, limit int) []Hit {
+	if offset >= len(hits) {
+		return nil
+	}
+	hits = hits[offset:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,149 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"farmer-to-buyer-portal/internal/money"
+)
+
+// testDocuments match "tomato" on fields of decreasing weight, so their
+// expected rank is their order here
+func testDocuments() []Document {
+	return []Document{
+		{ProductID: "crop-name", CropName: "Tomato", State: "Karnataka", Category: "vegetables", Price: money.Rupees(30), Active: true},
+		{ProductID: "keyword", CropName: "Tamatar", Keywords: []string{"Tomato", "Vegetables"}, State: "Karnataka", Category: "vegetables", Price: money.Rupees(15), Active: true},
+		{ProductID: "farm-name", CropName: "Onion", FarmName: "Tomato Valley Farm", State: "Maharashtra", Category: "vegetables", Price: money.Rupees(60), Active: true},
+		{ProductID: "prefix", CropName: "Chilli", Description: "Grown between rows of tomatoes", State: "Maharashtra", Category: "spices", Price: money.Rupees(30), Active: true},
+		{ProductID: "inactive", CropName: "Tomato", State: "Karnataka", Category: "vegetables", Price: money.Rupees(30)},
+		{ProductID: "unrelated", CropName: "Wheat", State: "Punjab", Category: "grains", Price: money.Rupees(25), Active: true},
+	}
+}
+
+func newTestMemory(t *testing.T) *Memory {
+	t.Helper()
+	m := NewMemory()
+	if err := m.Index(context.Background(), testDocuments()...); err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+	return m
+}
+
+func search(t *testing.T, m *Memory, q Query) Result {
+	t.Helper()
+	result, err := m.Search(context.Background(), q)
+	if err != nil {
+		t.Fatalf("Search(%+v) error = %v", q, err)
+	}
+	return result
+}
+
+func TestMemoryRanking(t *testing.T) {
+	m := newTestMemory(t)
+
+	result := search(t, m, Query{Text: "tomato"})
+	want := []Hit{
+		{ProductID: "crop-name", Score: weightCropName},
+		{ProductID: "keyword", Score: weightKeywords},
+		{ProductID: "farm-name", Score: weightFarmName},
+		{ProductID: "prefix", Score: weightDescription * prefixFactor},
+	}
+	if !reflect.DeepEqual(result.Hits, want) {
+		t.Errorf("hits = %+v, want %+v", result.Hits, want)
+	}
+	if result.Total != len(want) || result.CorrectedText != "" {
+		t.Errorf("total = %d, corrected = %q, want %d and no correction", result.Total, result.CorrectedText, len(want))
+	}
+
+	page := search(t, m, Query{Text: "tomato", Offset: 1, Limit: 2})
+	if !reflect.DeepEqual(page.Hits, want[1:3]) || page.Total != len(want) {
+		t.Errorf("page = %+v of %d, want %+v of %d", page.Hits, page.Total, want[1:3], len(want))
+	}
+}
+
+func TestMemoryCorrectsMisspelling(t *testing.T) {
+	m := newTestMemory(t)
+
+	result := search(t, m, Query{Text: "Tomatoe"})
+	if result.CorrectedText != "tomato" {
+		t.Errorf("corrected = %q, want tomato", result.CorrectedText)
+	}
+	if exact := search(t, m, Query{Text: "tomato"}); !reflect.DeepEqual(result.Hits, exact.Hits) {
+		t.Errorf("hits = %+v, want the hits for tomato %+v", result.Hits, exact.Hits)
+	}
+
+	// Description words are known words, even one edit away from a crop name
+	if plural := search(t, m, Query{Text: "tomatoes"}); plural.CorrectedText != "" {
+		t.Errorf("corrected = %q, want tomatoes left alone", plural.CorrectedText)
+	}
+
+	// Words under four letters must be spelled exactly
+	if short := search(t, m, Query{Text: "onin tmt"}); short.CorrectedText != "onion tmt" {
+		t.Errorf("corrected = %q, want onion tmt", short.CorrectedText)
+	}
+}
+
+func TestMemoryFacetsIgnoreTheirOwnFilter(t *testing.T) {
+	m := newTestMemory(t)
+
+	result := search(t, m, Query{Text: "tomato", State: "karnataka"})
+	if got := hitIDs(result.Hits); !reflect.DeepEqual(got, []string{"crop-name", "keyword"}) {
+		t.Errorf("hits = %v, want crop-name and keyword", got)
+	}
+	wantStates := []FacetCount{{"Karnataka", 2}, {"Maharashtra", 2}}
+	if !reflect.DeepEqual(result.Facets.State, wantStates) {
+		t.Errorf("state facet = %+v, want %+v", result.Facets.State, wantStates)
+	}
+	wantCategories := []FacetCount{{"vegetables", 2}}
+	if !reflect.DeepEqual(result.Facets.Category, wantCategories) {
+		t.Errorf("category facet = %+v, want %+v", result.Facets.Category, wantCategories)
+	}
+	wantBands := []FacetCount{{"under_20", 1}, {"20_50", 1}, {"50_100", 0}, {"100_500", 0}, {"500_plus", 0}}
+	if !reflect.DeepEqual(result.Facets.PriceBand, wantBands) {
+		t.Errorf("price band facet = %+v, want %+v", result.Facets.PriceBand, wantBands)
+	}
+
+	result = search(t, m, Query{Text: "tomato", Category: "vegetables", PriceBand: "20_50"})
+	if got := hitIDs(result.Hits); !reflect.DeepEqual(got, []string{"crop-name"}) {
+		t.Errorf("hits = %v, want crop-name", got)
+	}
+	wantStates = []FacetCount{{"Karnataka", 1}}
+	if !reflect.DeepEqual(result.Facets.State, wantStates) {
+		t.Errorf("state facet = %+v, want %+v", result.Facets.State, wantStates)
+	}
+	wantCategories = []FacetCount{{"spices", 1}, {"vegetables", 1}}
+	if !reflect.DeepEqual(result.Facets.Category, wantCategories) {
+		t.Errorf("category facet = %+v, want %+v", result.Facets.Category, wantCategories)
+	}
+	wantBands = []FacetCount{{"under_20", 1}, {"20_50", 1}, {"50_100", 1}, {"100_500", 0}, {"500_plus", 0}}
+	if !reflect.DeepEqual(result.Facets.PriceBand, wantBands) {
+		t.Errorf("price band facet = %+v, want %+v", result.Facets.PriceBand, wantBands)
+	}
+}
+
+func TestMemoryRejectsUnknownPriceBand(t *testing.T) {
+	m := newTestMemory(t)
+	if _, err := m.Search(context.Background(), Query{Text: "tomato", PriceBand: "cheap"}); !errors.Is(err, ErrUnknownPriceBand) {
+		t.Errorf("Search() error = %v, want ErrUnknownPriceBand", err)
+	}
+}
+
+func TestMemoryRemove(t *testing.T) {
+	m := newTestMemory(t)
+	if err := m.Remove(context.Background(), "crop-name", "keyword"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got := hitIDs(search(t, m, Query{Text: "tomato"}).Hits); !reflect.DeepEqual(got, []string{"farm-name", "prefix"}) {
+		t.Errorf("hits = %v, want farm-name and prefix", got)
+	}
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ProductID
+	}
+	return ids
+}
@@ -0,0 +1,209 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// matchColumns must list exactly the columns of the ft_products_search index
+const matchColumns = "products.crop_name, products.description, products.search_keywords"
+
+// vocabularyTTL is how long the words used for typo correction are cached
+const vocabularyTTL = 5 * time.Minute
+
+// MySQL is a Searcher over the FULLTEXT index of the products table. Listing
+// columns such as status, state and price are read live, so Index only has to
+// store the words that live in other tables.
+type MySQL struct {
+	db *gorm.DB
+
+	mu       sync.Mutex
+	vocab    vocabulary
+	loadedAt time.Time
+}
+
+// NewMySQL returns a searcher over db
+func NewMySQL(db *gorm.DB) *MySQL {
+	return &MySQL{db: db}
+}
+
+// Index implements Searcher by storing each document's keywords on its product
+func (s *MySQL) Index(ctx context.Context, docs ...Document) error {
+	for _, doc := range docs {
+		if err := s.db.WithContext(ctx).Model(&models.Product{}).
+			Where("id = ?", doc.ProductID).
+			UpdateColumn("search_keywords", doc.keywords()).Error; err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.vocab = nil
+	s.mu.Unlock()
+	return nil
+}
+
+// Remove implements Searcher. Deleted products leave the index with their row,
+// so there is nothing to do.
+func (s *MySQL) Remove(ctx context.Context, productIDs ...string) error {
+	return nil
+}
+
+// Search implements Searcher
+func (s *MySQL) Search(ctx context.Context, q Query) (Result, error) {
+	var band PriceBand
+	if q.PriceBand != "" {
+		var err error
+		if band, err = priceBand(q.PriceBand); err != nil {
+			return Result{}, err
+		}
+	}
+
+	vocab, err := s.vocabulary(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	words, changed := vocab.correct(q.Text)
+	result := Result{CorrectedText: correctedText(words, changed)}
+	if len(words) == 0 {
+		return result, nil
+	}
+
+	// Every word is optional and may match as a prefix; relevance decides the order
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = word + "*"
+	}
+	against := strings.Join(terms, " ")
+	matchExpr := "MATCH(" + matchColumns + ") AGAINST (? IN BOOLEAN MODE)"
+
+	base := func() *gorm.DB {
+		return s.db.WithContext(ctx).Model(&models.Product{}).
+			Where("products.status = ?", "active").
+			Where(matchExpr, against)
+	}
+
+	var total int64
+	if err := s.filter(base(), q, band, "").Count(&total).Error; err != nil {
+		return result, err
+	}
+	result.Total = int(total)
+
+	query := s.filter(base(), q, band, "").
+		Select("products.id AS product_id, "+matchExpr+" AS score", against).
+		Order("score DESC, products.id ASC")
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		query = query.Offset(q.Offset)
+	}
+	if err := query.Scan(&result.Hits).Error; err != nil {
+		return result, err
+	}
+
+	var states, categories, bands []FacetCount
+	if err := s.filter(base(), q, band, "state").
+		Select("products.state AS value, COUNT(*) AS count").
+		Group("products.state").
+		Scan(&states).Error; err != nil {
+		return result, err
+	}
+	if err := s.filter(base(), q, band, "category").
+		Joins("JOIN crops ON crops.id = products.crop_id").
+		Joins("JOIN crop_categories ON crop_categories.id = crops.category_id").
+		Select("crop_categories.slug AS value, COUNT(*) AS count").
+		Group("crop_categories.slug").
+		Scan(&categories).Error; err != nil {
+		return result, err
+	}
+	if err := s.filter(base(), q, band, "price_band").
+		Select(bandExpr() + " AS value, COUNT(*) AS count").
+		Group("value").
+		Scan(&bands).Error; err != nil {
+		return result, err
+	}
+
+	result.Facets = Facets{
+		State:     facetCounts(countsByValue(states)),
+		Category:  facetCounts(countsByValue(categories)),
+		PriceBand: bandCounts(countsByValue(bands)),
+	}
+	return result, nil
+}
+
+// filter applies the filters of q except the one named skip
+func (s *MySQL) filter(query *gorm.DB, q Query, band PriceBand, skip string) *gorm.DB {
+	if q.State != "" && skip != "state" {
+		query = query.Where("products.state = ?", q.State)
+	}
+	if q.Category != "" && skip != "category" {
+		query = query.Where("products.crop_id IN (?)", s.db.Model(&models.Crop{}).
+			Select("crops.id").
+			Joins("JOIN crop_categories ON crop_categories.id = crops.category_id").
+			Where("crop_categories.slug = ?", q.Category))
+	}
+	if q.PriceBand != "" && skip != "price_band" {
+		query = query.Where("products.price_per_unit_paise >= ?", band.Min)
+		if band.Max > 0 {
+			query = query.Where("products.price_per_unit_paise < ?", band.Max)
+		}
+	}
+	return query
+}
+
+// vocabulary returns the words of active listings, reloading them when stale
+func (s *MySQL) vocabulary(ctx context.Context) (vocabulary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.vocab != nil && time.Since(s.loadedAt) < vocabularyTTL {
+		return s.vocab, nil
+	}
+
+	var rows []struct {
+		CropName       string
+		Description    string
+		SearchKeywords string
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Product{}).
+		Distinct("crop_name", "description", "search_keywords").
+		Where("status = ?", "active").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	vocab := make(vocabulary)
+	for _, row := range rows {
+		vocab.addListing(row.CropName, row.Description, row.SearchKeywords)
+	}
+	s.vocab, s.loadedAt = vocab, time.Now()
+	return vocab, nil
+}
+
+// bandExpr is a SQL expression naming the price band of a product. It relies
+// on PriceBands being contiguous and in ascending order.
+func bandExpr() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, band := range PriceBands[:len(PriceBands)-1] {
+		fmt.Fprintf(&b, " WHEN products.price_per_unit_paise < %d THEN '%s'", int64(band.Max), band.Name)
+	}
+	fmt.Fprintf(&b, " ELSE '%s' END", PriceBands[len(PriceBands)-1].Name)
+	return b.String()
+}
+
+// countsByValue turns scanned facet rows into a map
+func countsByValue(rows []FacetCount) map[string]int {
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Value] += row.Count
+	}
+	return counts
+}
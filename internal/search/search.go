@@ -0,0 +1,168 @@
+// Package search finds product listings by free text, ranked by relevance,
+// with typo tolerance and facet counts.
+//
+// Searcher has two implementations: MySQL, backed by a FULLTEXT index on the
+// products table, and Memory, which keeps documents in process for tests and
+// local tools. Handlers call Reindex after changing anything a document is
+// built from so both stay current.
+package search
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"farmer-to-buyer-portal/internal/money"
+)
+
+// ErrUnknownPriceBand is returned for a price band that is not in PriceBands
+var ErrUnknownPriceBand = errors.New("unknown price band")
+
+// Document is the searchable form of one product listing
+type Document struct {
+	ProductID   string
+	CropName    string
+	Variety     string
+	Description string
+	FarmName    string
+	Keywords    []string // catalog name, category and synonyms of the linked crop
+	State       string
+	Category    string // category slug, empty when the listing is not linked to a crop
+	Price       money.Paise
+	Active      bool // listed and visible to buyers
+}
+
+// Query is a search request. Text is required; the other fields narrow it.
+type Query struct {
+	Text      string
+	State     string
+	Category  string
+	PriceBand string
+	Limit     int
+	Offset    int
+}
+
+// Hit is one matching listing and its relevance; higher scores rank first
+type Hit struct {
+	ProductID string
+	Score     float64
+}
+
+// FacetCount is the number of matches sharing one facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets break the matches down for refining a search. Each facet is counted
+// with every filter applied except its own, so a client can offer the other
+// values of a facet the user already picked.
+type Facets struct {
+	State     []FacetCount `json:"state"`
+	Category  []FacetCount `json:"category"`
+	PriceBand []FacetCount `json:"price_band"`
+}
+
+// Result is one page of hits with the totals for the whole match
+type Result struct {
+	Hits  []Hit
+	Total int
+	// CorrectedText is the query after typo correction, empty when nothing changed
+	CorrectedText string
+	Facets        Facets
+}
+
+// Searcher indexes and searches product listings
+type Searcher interface {
+	// Index adds or replaces documents
+	Index(ctx context.Context, docs ...Document) error
+	// Remove deletes the documents of the given products
+	Remove(ctx context.Context, productIDs ...string) error
+	// Search returns active listings matching q, best match first
+	Search(ctx context.Context, q Query) (Result, error)
+}
+
+// PriceBand is a range of prices per unit, Min inclusive and Max exclusive.
+// A zero Max leaves the band open-ended.
+type PriceBand struct {
+	Name string
+	Min  money.Paise
+	Max  money.Paise
+}
+
+// PriceBands partition prices per unit for faceting
+var PriceBands = []PriceBand{
+	{Name: "under_20", Min: 0, Max: money.Rupees(20)},
+	{Name: "20_50", Min: money.Rupees(20), Max: money.Rupees(50)},
+	{Name: "50_100", Min: money.Rupees(50), Max: money.Rupees(100)},
+	{Name: "100_500", Min: money.Rupees(100), Max: money.Rupees(500)},
+	{Name: "500_plus", Min: money.Rupees(500)},
+}
+
+// priceBand returns the band named name
+func priceBand(name string) (PriceBand, error) {
+	for _, band := range PriceBands {
+		if band.Name == name {
+			return band, nil
+		}
+	}
+	return PriceBand{}, ErrUnknownPriceBand
+}
+
+// bandOf returns the name of the band price falls in
+func bandOf(price money.Paise) string {
+	for _, band := range PriceBands {
+		if price >= band.Min && (band.Max == 0 || price < band.Max) {
+			return band.Name
+		}
+	}
+	return ""
+}
+
+// keywords joins the fields of doc that are not columns of the products table
+func (doc Document) keywords() string {
+	fields := append([]string{doc.Variety, doc.FarmName}, doc.Keywords...)
+	var parts []string
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			parts = append(parts, f)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Tokenize lower-cases text and splits it into words. Combining marks stay
+// part of their word so Indic scripts split only at spaces and punctuation.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+}
+
+// facetCounts orders facet values by descending count, then by value
+func facetCounts(counts map[string]int) []FacetCount {
+	facets := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		if value != "" && count > 0 {
+			facets = append(facets, FacetCount{Value: value, Count: count})
+		}
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
+
+// bandCounts lists every price band in ascending order, including empty ones
+func bandCounts(counts map[string]int) []FacetCount {
+	facets := make([]FacetCount, len(PriceBands))
+	for i, band := range PriceBands {
+		facets[i] = FacetCount{Value: band.Name, Count: counts[band.Name]}
+	}
+	return facets
+}
@@ -0,0 +1,231 @@
+// Package seed fills a development database with demo farmers, buyers and products.
+package seed
+
+import (
+	"errors"
+	"fmt"
+
+	"farmer-to-buyer-portal/internal/catalog"
+	"farmer-to-buyer-portal/internal/geo"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/money"
+	"farmer-to-buyer-portal/internal/units"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Summary counts the records created by Run
+type Summary struct {
+	Users    int
+	Products int
+	Skipped  int // demo users that already existed
+}
+
+type demoProduct struct {
+	CropName     string
+	Quantity     float64
+	Unit         string
+	PricePerUnit money.Paise
+}
+
+type demoFarmer struct {
+	Phone    string
+	Name     string
+	Profile  models.FarmerProfile
+	Products []demoProduct
+}
+
+type demoBuyer struct {
+	Phone   string
+	Name    string
+	Profile models.BuyerProfile
+}
+
+var farmers = []demoFarmer{
+	{
+		Phone: "9000000001",
+		Name:  "Murugan K",
+		Profile: models.FarmerProfile{
+			FarmName: "Kaveri Green Farms", State: "Tamil Nadu", City: "Coimbatore",
+			Pincode: "641001", FarmSizeAcres: 12.5,
+		},
+		Products: []demoProduct{
+			{"Tomato", 500, "kg", money.Rupees(22)},
+			{"Onion", 800, "kg", money.Rupees(30)},
+			{"Banana", 120, "dozen", money.Rupees(45)},
+		},
+	},
+	{
+		Phone: "9000000002",
+		Name:  "Lakshmi Devi",
+		Profile: models.FarmerProfile{
+			FarmName: "Deccan Organics", State: "Karnataka", City: "Mysuru",
+			Pincode: "570001", FarmSizeAcres: 8,
+		},
+		Products: []demoProduct{
+			{"Ragi", 20, "quintal", money.Rupees(3800)},
+			{"Coconut", 1000, "piece", money.Rupees(18)},
+			{"Green Chilli", 150, "kg", money.Rupees(40)},
+		},
+	},
+	{
+		Phone: "9000000003",
+		Name:  "Sandeep Patil",
+		Profile: models.FarmerProfile{
+			FarmName: "Sahyadri Orchards", State: "Maharashtra", City: "Nashik",
+			Pincode: "422001", FarmSizeAcres: 20,
+		},
+		Products: []demoProduct{
+			{"Grapes", 600, "kg", money.Rupees(65)},
+			{"Pomegranate", 300, "kg", money.Rupees(90)},
+		},
+	},
+}
+
+// pincodes places the demo pincodes, approximately, for distance search when
+// no reference data has been loaded. Loaded data takes precedence.
+var pincodes = []models.Pincode{
+	{Code: "641001", Latitude: 11.0017, Longitude: 76.9629, District: "Coimbatore", State: "Tamil Nadu"},
+	{Code: "570001", Latitude: 12.3106, Longitude: 76.6528, District: "Mysuru", State: "Karnataka"},
+	{Code: "422001", Latitude: 19.9975, Longitude: 73.7898, District: "Nashik", State: "Maharashtra"},
+	{Code: "600001", Latitude: 13.0900, Longitude: 80.2880, District: "Chennai", State: "Tamil Nadu"},
+	{Code: "560001", Latitude: 12.9762, Longitude: 77.6033, District: "Bengaluru", State: "Karnataka"},
+}
+
+var buyers = []demoBuyer{
+	{
+		Phone: "9100000001",
+		Name:  "Anand Restaurant",
+		Profile: models.BuyerProfile{
+			BuyerType: "restaurant", BusinessName: "Anand Bhavan", State: "Tamil Nadu",
+			City: "Chennai", Pincode: "600001",
+		},
+	},
+	{
+		Phone: "9100000002",
+		Name:  "Priya Sharma",
+		Profile: models.BuyerProfile{
+			BuyerType: "individual", State: "Karnataka", City: "Bengaluru", Pincode: "560001",
+		},
+	},
+}
+
+// Run creates the demo accounts, all with password, and the farmers' listings.
+// Accounts that already exist are left untouched, so Run can be repeated.
+func Run(db *gorm.DB, password string) (Summary, error) {
+	var summary Summary
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return summary, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&pincodes).Error; err != nil {
+			return fmt.Errorf("failed to create demo pincodes: %w", err)
+		}
+
+		for _, f := range farmers {
+			user, created, err := createUser(tx, f.Phone, f.Name, "farmer", string(hashedPassword))
+			if err != nil {
+				return err
+			}
+			if !created {
+				summary.Skipped++
+				continue
+			}
+			summary.Users++
+
+			point, err := geo.Locate(tx, f.Profile.Pincode)
+			if err != nil {
+				return err
+			}
+			profile := f.Profile
+			profile.FarmerID = user.ID
+			profile.Latitude, profile.Longitude = point.LatLng()
+			if err := tx.Create(&profile).Error; err != nil {
+				return fmt.Errorf("failed to create farmer profile for %s: %w", f.Phone, err)
+			}
+
+			for _, p := range f.Products {
+				product := models.Product{
+					FarmerID:     user.ID,
+					CropName:     p.CropName,
+					Quantity:     p.Quantity,
+					Unit:         p.Unit,
+					PricePerUnit: p.PricePerUnit,
+					PricePerKg:   units.PricePerKg(p.PricePerUnit, p.Unit),
+					State:        profile.State,
+					City:         profile.City,
+					Pincode:      profile.Pincode,
+					Latitude:     profile.Latitude,
+					Longitude:    profile.Longitude,
+					Status:       "active",
+				}
+				crop, err := catalog.Resolve(tx, p.CropName)
+				if err != nil {
+					return fmt.Errorf("failed to look up crop %s: %w", p.CropName, err)
+				}
+				if crop != nil {
+					product.CropID = &crop.ID
+				}
+				if err := tx.Create(&product).Error; err != nil {
+					return fmt.Errorf("failed to create product %s: %w", p.CropName, err)
+				}
+				summary.Products++
+			}
+		}
+
+		for _, b := range buyers {
+			user, created, err := createUser(tx, b.Phone, b.Name, "buyer", string(hashedPassword))
+			if err != nil {
+				return err
+			}
+			if !created {
+				summary.Skipped++
+				continue
+			}
+			summary.Users++
+
+			point, err := geo.Locate(tx, b.Profile.Pincode)
+			if err != nil {
+				return err
+			}
+			profile := b.Profile
+			profile.BuyerID = user.ID
+			profile.Latitude, profile.Longitude = point.LatLng()
+			if err := tx.Create(&profile).Error; err != nil {
+				return fmt.Errorf("failed to create buyer profile for %s: %w", b.Phone, err)
+			}
+		}
+		return nil
+	})
+	return summary, err
+}
+
+// createUser creates a verified demo user unless the phone is already registered
+func createUser(tx *gorm.DB, phone, name, role, passwordHash string) (models.User, bool, error) {
+	var user models.User
+	err := tx.Where("phone = ?", phone).First(&user).Error
+	if err == nil {
+		return user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, false, err
+	}
+
+	user = models.User{
+		Phone:        phone,
+		Name:         name,
+		PasswordHash: passwordHash,
+		Role:         role,
+		IsVerified:   true,
+		IsActive:     true,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return user, false, fmt.Errorf("failed to create user %s: %w", phone, err)
+	}
+	return user, true, nil
+}
@@ -0,0 +1,60 @@
+package sms
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"farmer-to-buyer-portal/internal/config"
+)
+
+// Sender delivers a text message to a phone number
+type Sender interface {
+	Send(phone, message string) error
+}
+
+// NewSender builds the sender selected by SMS_SENDER
+func NewSender(cfg config.Config) (Sender, error) {
+	switch cfg.SMSSender {
+	case "", "console":
+		return ConsoleSender{}, nil
+	case "file":
+		return &FileSender{Path: cfg.SMSFilePath}, nil
+	default:
+		return nil, fmt.Errorf("unknown SMS sender %q", cfg.SMSSender)
+	}
+}
+
+// ConsoleSender writes messages to the application log. Intended for local development only.
+type ConsoleSender struct{}
+
+// Send logs the message
+func (ConsoleSender) Send(phone, message string) error {
+	log.Printf("INFO: SMS to %s: %s", phone, message)
+	return nil
+}
+
+// FileSender appends messages to a file, one per line. Intended for local development and tests.
+type FileSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Send appends the message to the outbox file
+func (s *FileSender) Send(phone, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open SMS outbox: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), phone, message); err != nil {
+		return fmt.Errorf("failed to write SMS outbox: %w", err)
+	}
+	return nil
+}
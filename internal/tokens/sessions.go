@@ -0,0 +1,92 @@
+package tokens
+
+import (
+	"errors"
+	"time"
+
+	"farmer-to-buyer-portal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrSessionNotFound is returned when a session does not exist or belongs to another user
+var ErrSessionNotFound = errors.New("session not found")
+
+// lastSeenResolution limits how often LastSeenAt is written for an active session
+const lastSeenResolution = time.Minute
+
+// ActiveSessions lists a user's sessions that have not been revoked, most recent first
+func ActiveSessions(db *gorm.DB, userID string) ([]models.Session, error) {
+	var sessions []models.Session
+	err := db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// CheckSession verifies that sessionID is a live session of userID and records
+// activity on it
+func CheckSession(db *gorm.DB, userID, sessionID string) error {
+	if sessionID == "" {
+		return ErrSessionNotFound
+	}
+
+	var session models.Session
+	if err := db.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+
+	if now := time.Now(); now.Sub(session.LastSeenAt) > lastSeenResolution {
+		if err := db.Model(&session).Update("last_seen_at", now).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeSession revokes one of a user's sessions and its refresh tokens
+func RevokeSession(db *gorm.DB, userID, sessionID string) error {
+	now := time.Now()
+	result := db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", now).Error
+}
+
+// RevokeAllSessions revokes every session of a user and all their refresh tokens
+func RevokeAllSessions(db *gorm.DB, userID string) error {
+	now := time.Now()
+	if err := db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	return db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+}
+
+// revokeSessionIfLive is RevokeSession for callers that do not care whether the
+// session was already revoked
+func revokeSessionIfLive(db *gorm.DB, userID, sessionID string) error {
+	if err := RevokeSession(db, userID, sessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+	return nil
+}
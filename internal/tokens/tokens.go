@@ -0,0 +1,197 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidRefreshToken is returned for unknown, expired or unusable refresh tokens
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// ErrRefreshTokenReused is returned when an already rotated token is presented
+// again; the whole token family is revoked when this happens
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+
+var refreshTokenTTL = 30 * 24 * time.Hour
+
+// Init configures the refresh token lifetime from config
+func Init(cfg config.Config) {
+	if cfg.RefreshTokenTTL > 0 {
+		refreshTokenTTL = cfg.RefreshTokenTTL
+	}
+}
+
+// Pair is an access token with the refresh token that can renew it
+type Pair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+	SessionID    string
+
+	refreshTokenID string
+}
+
+// Client describes the device a session was started or refreshed from
+type Client struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Issue starts a new session for user and returns its first token pair
+func Issue(db *gorm.DB, user models.User, client Client) (Pair, error) {
+	var pair Pair
+	err := db.Transaction(func(tx *gorm.DB) error {
+		session := models.Session{
+			UserID:     user.ID,
+			UserAgent:  truncate(client.UserAgent, 255),
+			IPAddress:  client.IPAddress,
+			LastSeenAt: time.Now(),
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+
+		var err error
+		pair, err = issue(tx, user, session.ID)
+		return err
+	})
+	return pair, err
+}
+
+// Rotate exchanges a refresh token for a new pair in the same session. Presenting
+// a token that was already rotated revokes the entire session.
+func Rotate(db *gorm.DB, rawToken string, client Client) (Pair, models.User, error) {
+	var pair Pair
+	var user models.User
+
+	// Reuse must still commit the family revocation, so it is reported through
+	// result rather than by failing the transaction
+	var result error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashToken(rawToken)).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result = ErrInvalidRefreshToken
+				return nil
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			// A rotated token has a successor; one without was revoked by logout
+			if current.ReplacedBy == "" {
+				result = ErrInvalidRefreshToken
+				return nil
+			}
+			result = ErrRefreshTokenReused
+			return revokeSessionIfLive(tx, current.UserID, current.FamilyID)
+		}
+		if time.Now().After(current.ExpiresAt) {
+			result = ErrInvalidRefreshToken
+			return nil
+		}
+
+		if err := tx.Where("id = ?", current.UserID).First(&user).Error; err != nil {
+			return err
+		}
+		if !user.IsActive {
+			result = ErrInvalidRefreshToken
+			return revokeSessionIfLive(tx, current.UserID, current.FamilyID)
+		}
+
+		if err := tx.Model(&models.Session{}).
+			Where("id = ?", current.FamilyID).
+			Updates(map[string]interface{}{
+				"last_seen_at": time.Now(),
+				"user_agent":   truncate(client.UserAgent, 255),
+				"ip_address":   client.IPAddress,
+			}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		pair, err = issue(tx, user, current.FamilyID)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&current).Updates(map[string]interface{}{
+			"revoked_at":  time.Now(),
+			"replaced_by": pair.refreshTokenID,
+		}).Error
+	})
+	if err != nil {
+		return Pair{}, models.User{}, err
+	}
+	if result != nil {
+		return Pair{}, models.User{}, result
+	}
+	return pair, user, nil
+}
+
+// issue stores a new refresh token in the session's family and signs a matching access token
+func issue(db *gorm.DB, user models.User, sessionID string) (Pair, error) {
+	raw, err := generateRefreshToken()
+	if err != nil {
+		return Pair{}, err
+	}
+
+	refreshToken := models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if err := db.Create(&refreshToken).Error; err != nil {
+		return Pair{}, err
+	}
+
+	accessToken, err := utils.GenerateToken(user.ID, user.Role, user.TokenVersion, sessionID)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	return Pair{
+		AccessToken:  accessToken,
+		RefreshToken: raw,
+		ExpiresIn:    utils.AccessTokenTTL(),
+		SessionID:    sessionID,
+
+		refreshTokenID: refreshToken.ID,
+	}, nil
+}
+
+// generateRefreshToken returns 256 random bits, base64url encoded
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex SHA-256 of a raw refresh token; only hashes are stored
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"farmer-to-buyer-portal/internal/config"
+	"farmer-to-buyer-portal/internal/dbtest"
+	"farmer-to-buyer-portal/internal/models"
+	"farmer-to-buyer-portal/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// newUser creates an active user and starts a session for it
+func newUser(t *testing.T, db *gorm.DB) (models.User, Pair) {
+	t.Helper()
+	if err := utils.InitJWT(config.Config{AppEnv: "production", JWTSecret: "a-long-random-secret", JWTAlgorithm: "HS256"}); err != nil {
+		t.Fatalf("InitJWT() error = %v", err)
+	}
+
+	user := models.User{Phone: fmt.Sprintf("9%09d", rand.Int63n(1e9)), Name: "Test", PasswordHash: "x", Role: "buyer", IsActive: true}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	pair, err := Issue(db, user, Client{UserAgent: "test"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	return user, pair
+}
+
+func TestRotate(t *testing.T) {
+	db := dbtest.Open(t)
+	user, first := newUser(t, db)
+
+	second, rotatedFor, err := Rotate(db, first.RefreshToken, Client{UserAgent: "test"})
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if rotatedFor.ID != user.ID {
+		t.Errorf("Rotate() user = %s, want %s", rotatedFor.ID, user.ID)
+	}
+	if second.SessionID != first.SessionID {
+		t.Errorf("Rotate() session = %s, want %s", second.SessionID, first.SessionID)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("Rotate() returned the same refresh token")
+	}
+	if err := CheckSession(db, user.ID, second.SessionID); err != nil {
+		t.Errorf("CheckSession() after rotation error = %v", err)
+	}
+}
+
+func TestRotateReuseRevokesFamily(t *testing.T) {
+	db := dbtest.Open(t)
+	user, first := newUser(t, db)
+
+	second, _, err := Rotate(db, first.RefreshToken, Client{})
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	if _, _, err := Rotate(db, first.RefreshToken, Client{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Rotate(old token) error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	if _, _, err := Rotate(db, second.RefreshToken, Client{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Rotate(successor) after reuse error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+	if err := CheckSession(db, user.ID, first.SessionID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("CheckSession() after reuse error = %v, want %v", err, ErrSessionNotFound)
+	}
+
+	var live int64
+	if err := db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", first.SessionID).
+		Count(&live).Error; err != nil {
+		t.Fatalf("failed to count refresh tokens: %v", err)
+	}
+	if live != 0 {
+		t.Errorf("%d refresh tokens of the family are still live", live)
+	}
+}
+
+func TestRotateAfterLogout(t *testing.T) {
+	db := dbtest.Open(t)
+	user, pair := newUser(t, db)
+
+	if err := RevokeSession(db, user.ID, pair.SessionID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if _, _, err := Rotate(db, pair.RefreshToken, Client{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Rotate(logged out token) error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestRotateUnknownToken(t *testing.T) {
+	db := dbtest.Open(t)
+	if _, _, err := Rotate(db, "not-a-token", Client{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Rotate(unknown token) error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
@@ -0,0 +1,119 @@
+// Package units is the catalog of units products can be listed in, with the
+// conversions needed to compare listings across units.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"farmer-to-buyer-portal/internal/money"
+)
+
+// Dimension is what a unit measures. Only units of the same dimension convert.
+type Dimension string
+
+// Dimensions and their base units
+const (
+	Mass   Dimension = "mass"   // base unit kg
+	Count  Dimension = "count"  // base unit piece
+	Volume Dimension = "volume" // base unit litre
+)
+
+// Unit is a canonical unit of measure. One unit equals Num/Den base units of
+// its dimension; keeping the factor rational makes price conversions exact.
+type Unit struct {
+	Code      string
+	Name      string
+	Dimension Dimension
+	Num       int64
+	Den       int64
+	Aliases   []string
+}
+
+// catalog lists every unit; the first unit of each dimension is its base unit
+var catalog = []Unit{
+	{Code: "kg", Name: "Kilogram", Dimension: Mass, Num: 1, Den: 1, Aliases: []string{"kgs", "kilo", "kilos", "kilogram", "kilograms"}},
+	{Code: "g", Name: "Gram", Dimension: Mass, Num: 1, Den: 1000, Aliases: []string{"gm", "gms", "gram", "grams"}},
+	{Code: "quintal", Name: "Quintal (100 kg)", Dimension: Mass, Num: 100, Den: 1, Aliases: []string{"quintals", "qtl", "qtls"}},
+	{Code: "tonne", Name: "Tonne (1000 kg)", Dimension: Mass, Num: 1000, Den: 1, Aliases: []string{"tonnes", "ton", "tons", "mt"}},
+	{Code: "piece", Name: "Piece", Dimension: Count, Num: 1, Den: 1, Aliases: []string{"pieces", "pc", "pcs", "nos", "no"}},
+	{Code: "dozen", Name: "Dozen (12 pieces)", Dimension: Count, Num: 12, Den: 1, Aliases: []string{"dozens", "doz", "dz"}},
+	{Code: "litre", Name: "Litre", Dimension: Volume, Num: 1, Den: 1, Aliases: []string{"litres", "liter", "liters", "l", "ltr", "ltrs"}},
+	{Code: "ml", Name: "Millilitre", Dimension: Volume, Num: 1, Den: 1000, Aliases: []string{"millilitre", "millilitres", "milliliter", "milliliters"}},
+}
+
+// ErrIncompatible is returned when converting between different dimensions
+var ErrIncompatible = errors.New("units measure different dimensions")
+
+// byName indexes every code and alias, lower-cased
+var byName = func() map[string]Unit {
+	index := make(map[string]Unit)
+	for _, u := range catalog {
+		for _, name := range append([]string{u.Code}, u.Aliases...) {
+			if _, dup := index[name]; dup {
+				panic(fmt.Sprintf("units: %q is declared twice", name))
+			}
+			index[name] = u
+		}
+	}
+	return index
+}()
+
+// All returns the catalog ordered by dimension, base unit first
+func All() []Unit {
+	all := append([]Unit(nil), catalog...)
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Dimension < all[j].Dimension })
+	return all
+}
+
+// Codes returns the canonical unit codes
+func Codes() []string {
+	codes := make([]string, len(catalog))
+	for i, u := range catalog {
+		codes[i] = u.Code
+	}
+	return codes
+}
+
+// Lookup finds a unit by code or alias, ignoring case, spaces and a trailing dot
+func Lookup(name string) (Unit, bool) {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+	u, ok := byName[name]
+	return u, ok
+}
+
+// Convert converts quantity from one unit to another of the same dimension
+func Convert(quantity float64, from, to Unit) (float64, error) {
+	if from.Dimension != to.Dimension {
+		return 0, ErrIncompatible
+	}
+	return quantity * float64(from.Num*to.Den) / float64(from.Den*to.Num), nil
+}
+
+// PricePerBase converts a price per unit into a price per base unit of the
+// unit's dimension (per kg, per piece or per litre), rounded to the nearest
+// paisa with halves away from zero
+func PricePerBase(price money.Paise, u Unit) money.Paise {
+	// price per base unit = price × Den / Num
+	numerator := int64(price) * u.Den
+	quotient, remainder := numerator/u.Num, numerator%u.Num
+	if 2*remainder >= u.Num {
+		quotient++
+	} else if 2*remainder <= -u.Num {
+		quotient--
+	}
+	return money.Paise(quotient)
+}
+
+// PricePerKg returns the price per kg for mass units and nil for other units,
+// whose prices cannot be compared by weight
+func PricePerKg(price money.Paise, code string) *money.Paise {
+	u, ok := Lookup(code)
+	if !ok || u.Dimension != Mass {
+		return nil
+	}
+	perKg := PricePerBase(price, u)
+	return &perKg
+}
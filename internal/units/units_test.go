@@ -0,0 +1,118 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"farmer-to-buyer-portal/internal/money"
+)
+
+func mustLookup(t *testing.T, name string) Unit {
+	t.Helper()
+	u, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("Lookup(%q) found nothing", name)
+	}
+	return u
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"kg", "kg"},
+		{" KGS. ", "kg"},
+		{"Qtl", "quintal"},
+		{"gms", "g"},
+		{"Doz.", "dozen"},
+		{"Liters", "litre"},
+	}
+	for _, tt := range tests {
+		if got := mustLookup(t, tt.name); got.Code != tt.want {
+			t.Errorf("Lookup(%q) = %s, want %s", tt.name, got.Code, tt.want)
+		}
+	}
+	if _, ok := Lookup("bushel"); ok {
+		t.Error("Lookup(bushel) found a unit")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		from, to string
+		want     float64
+	}{
+		{2.5, "quintal", "kg", 250},
+		{1500, "g", "kg", 1.5},
+		{0.25, "kg", "g", 250},
+		{1, "tonne", "quintal", 10},
+		{3, "dozen", "piece", 36},
+		{6, "piece", "dozen", 0.5},
+		{750, "ml", "litre", 0.75},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.quantity, mustLookup(t, tt.from), mustLookup(t, tt.to))
+		if err != nil || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v %s -> %s) = %v, %v, want %v", tt.quantity, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+
+	if _, err := Convert(1, mustLookup(t, "kg"), mustLookup(t, "dozen")); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Convert(kg -> dozen) error = %v, want %v", err, ErrIncompatible)
+	}
+}
+
+func TestPricePerBase(t *testing.T) {
+	tests := []struct {
+		price money.Paise
+		unit  string
+		want  money.Paise
+	}{
+		{money.Rupees(2500), "quintal", money.Rupees(25)},
+		{money.Rupees(40), "kg", money.Rupees(40)},
+		{5, "g", money.Rupees(50)},
+		{money.Rupees(60), "dozen", money.Rupees(5)},
+		{100001, "quintal", 1000},   // 1000.01
+		{100050, "quintal", 1001},   // 1000.5 rounds away from zero
+		{-100050, "quintal", -1001}, // and so does -1000.5
+		{6, "dozen", 1},             // 0.5
+		{5, "dozen", 0},             // 0.42
+		{7, "tonne", 0},             // 0.007
+	}
+	for _, tt := range tests {
+		if got := PricePerBase(tt.price, mustLookup(t, tt.unit)); got != tt.want {
+			t.Errorf("PricePerBase(%d per %s) = %d, want %d", tt.price, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestPricePerKg(t *testing.T) {
+	tests := []struct {
+		price money.Paise
+		code  string
+		want  *money.Paise
+	}{
+		{money.Rupees(3000), "quintal", paise(money.Rupees(30))},
+		{12, "g", paise(money.Rupees(120))},
+		{money.Rupees(45), "kg", paise(money.Rupees(45))},
+		{money.Rupees(60), "dozen", nil},
+		{money.Rupees(60), "litre", nil},
+		{money.Rupees(60), "bushel", nil},
+	}
+	for _, tt := range tests {
+		got := PricePerKg(tt.price, tt.code)
+		switch {
+		case tt.want == nil && got != nil:
+			t.Errorf("PricePerKg(%d per %s) = %d, want nil", tt.price, tt.code, *got)
+		case tt.want != nil && (got == nil || *got != *tt.want):
+			t.Errorf("PricePerKg(%d per %s) = %v, want %d", tt.price, tt.code, got, *tt.want)
+		}
+	}
+}
+
+func paise(p money.Paise) *money.Paise {
+	return &p
+}
@@ -2,58 +2,180 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"farmer-to-buyer-portal/internal/config"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-var jwtSecret []byte
+// defaultJWTSecret is the placeholder secret config.Load falls back to
+const defaultJWTSecret = "changeme"
+
+var (
+	ring           *keyring
+	accessTokenTTL = 15 * time.Minute
+)
 
 // Claims represents JWT token claims
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID       string `json:"user_id"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"tv"`
+	SessionID    string `json:"sid"` // the refresh token family the token was issued for
 	jwt.RegisteredClaims
 }
 
-// InitJWT initializes JWT secret from config
-func InitJWT(cfg config.Config) {
-	jwtSecret = []byte(cfg.JWTSecret)
+// InitJWT builds the signing keyring and access token lifetime from config.
+//
+// With JWT_ALGORITHM=HS256 tokens are signed with JWT_SECRET, and
+// JWT_PREVIOUS_SECRETS (comma separated) keep verifying tokens signed before a
+// secret rotation. With RS256 or EdDSA every PEM key in JWT_KEYS_DIR verifies
+// tokens and JWT_ACTIVE_KID selects the one that signs; a non-default JWT_SECRET
+// keeps verifying HS256 tokens issued before the switch.
+//
+// HS256 never runs with an empty secret, and outside development it refuses
+// the default one too.
+func InitJWT(cfg config.Config) error {
+	if cfg.AccessTokenTTL > 0 {
+		accessTokenTTL = cfg.AccessTokenTTL
+	}
+
+	defaultSecret := cfg.JWTSecret == "" || cfg.JWTSecret == defaultJWTSecret
+	if cfg.JWTAlgorithm == "HS256" {
+		if cfg.JWTSecret == "" {
+			return errors.New("refusing to start with an empty JWT_SECRET; set JWT_SECRET or use RS256/EdDSA keys")
+		}
+		if defaultSecret && !cfg.IsDevelopment() {
+			return errors.New("refusing to start with the default JWT_SECRET outside development; set JWT_SECRET or use RS256/EdDSA keys")
+		}
+	}
+
+	r := &keyring{keys: make(map[string]*signingKey)}
+
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		if defaultSecret {
+			log.Println("WARNING: using the default JWT_SECRET - development only")
+		}
+		active := hmacKey(cfg.JWTSecret)
+		if err := r.add(active); err != nil {
+			return err
+		}
+		r.active, r.legacy = active, active
+		for _, previous := range strings.Split(cfg.JWTPreviousSecrets, ",") {
+			if previous = strings.TrimSpace(previous); previous == "" {
+				continue
+			}
+			if err := r.add(hmacKey(previous)); err != nil {
+				return err
+			}
+		}
+
+	case "RS256", "EdDSA":
+		if cfg.JWTKeysDir == "" {
+			return fmt.Errorf("JWT_KEYS_DIR is required for %s", cfg.JWTAlgorithm)
+		}
+		keys, err := loadKeyDir(cfg.JWTKeysDir)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := r.add(key); err != nil {
+				return err
+			}
+		}
+
+		activeKID := cfg.JWTActiveKID
+		if activeKID == "" && len(keys) == 1 {
+			activeKID = keys[0].kid
+		}
+		active, ok := r.keys[activeKID]
+		if !ok {
+			return fmt.Errorf("JWT_ACTIVE_KID %q not found in %s", activeKID, cfg.JWTKeysDir)
+		}
+		if active.signer == nil {
+			return fmt.Errorf("JWT key %q is public only and cannot sign", activeKID)
+		}
+		if active.method.Alg() != cfg.JWTAlgorithm {
+			return fmt.Errorf("JWT key %q is %s but JWT_ALGORITHM is %s", activeKID, active.method.Alg(), cfg.JWTAlgorithm)
+		}
+		r.active = active
+
+		// Keep accepting tokens signed with the old shared secret during migration
+		if !defaultSecret {
+			legacy := hmacKey(cfg.JWTSecret)
+			if err := r.add(legacy); err != nil {
+				return err
+			}
+			r.legacy = legacy
+		}
+
+	default:
+		return fmt.Errorf("unsupported JWT_ALGORITHM %q, expected HS256, RS256 or EdDSA", cfg.JWTAlgorithm)
+	}
+
+	ring = r
+	log.Printf("INFO: JWT keyring loaded - algorithm: %s, active kid: %s, verification keys: %d",
+		r.active.method.Alg(), r.active.kid, len(r.keys))
+	return nil
+}
+
+// AccessTokenTTL returns how long newly issued access tokens stay valid
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
 }
 
-// GenerateToken generates a JWT token for a user
-func GenerateToken(userID, role string) (string, error) {
-	if jwtSecret == nil {
-		return "", errors.New("JWT secret not initialized")
+// GenerateToken generates a short-lived access token for a user. tokenVersion must
+// match the user's current TokenVersion for the token to be accepted, and
+// sessionID (the sid claim) ties the token to its refresh token family. Every
+// token gets its own random jti.
+func GenerateToken(userID, role string, tokenVersion int, sessionID string) (string, error) {
+	if ring == nil {
+		return "", errors.New("JWT keyring not initialized")
 	}
 
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:       userID,
+		Role:         role,
+		TokenVersion: tokenVersion,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token := jwt.NewWithClaims(ring.active.method, claims)
+	token.Header["kid"] = ring.active.kid
+	return token.SignedString(ring.active.signer)
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// ValidateToken validates a JWT token and returns the claims. The verification
+// key is selected by the kid header and must match the token's algorithm.
 func ValidateToken(tokenString string) (*Claims, error) {
-	if jwtSecret == nil {
-		return nil, errors.New("JWT secret not initialized")
+	if ring == nil {
+		return nil, errors.New("JWT keyring not initialized")
 	}
 
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		key := ring.legacy
+		if kid, ok := token.Header["kid"].(string); ok {
+			key = ring.keys[kid]
+		}
+		if key == nil {
+			return nil, errors.New("unknown signing key")
+		}
+		if token.Method.Alg() != key.method.Alg() {
 			return nil, errors.New("invalid signing method")
 		}
-		return jwtSecret, nil
-	})
+		return key.verify, nil
+	}, jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}))
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+
+	"farmer-to-buyer-portal/internal/config"
+)
+
+func TestInitJWTSecretChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		secret  string
+		wantErr bool
+	}{
+		{"empty secret in production", "production", "", true},
+		{"empty secret in development", "development", "", true},
+		{"default secret in production", "production", defaultJWTSecret, true},
+		{"default secret in development", "development", defaultJWTSecret, false},
+		{"unknown environment", "", defaultJWTSecret, true},
+		{"real secret in production", "production", "a-long-random-secret", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := InitJWT(config.Config{AppEnv: tt.env, JWTSecret: tt.secret, JWTAlgorithm: "HS256"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("InitJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateTokenClaims(t *testing.T) {
+	if err := InitJWT(config.Config{AppEnv: "production", JWTSecret: "a-long-random-secret", JWTAlgorithm: "HS256"}); err != nil {
+		t.Fatalf("InitJWT() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		token, err := GenerateToken("user-1", "buyer", 2, "session-1")
+		if err != nil {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		claims, err := ValidateToken(token)
+		if err != nil {
+			t.Fatalf("ValidateToken() error = %v", err)
+		}
+		if claims.UserID != "user-1" || claims.Role != "buyer" || claims.TokenVersion != 2 {
+			t.Errorf("claims = %+v, want user-1, buyer, version 2", claims)
+		}
+		if claims.SessionID != "session-1" {
+			t.Errorf("sid = %q, want session-1", claims.SessionID)
+		}
+		if claims.ID == "" || claims.ID == claims.SessionID || seen[claims.ID] {
+			t.Errorf("jti = %q, want a fresh random ID per token", claims.ID)
+		}
+		seen[claims.ID] = true
+	}
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"farmer-to-buyer-portal/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testKeys are generated once; RSA key generation is slow
+var testKeys = struct {
+	rsa *rsa.PrivateKey
+	ed  ed25519.PrivateKey
+}{}
+
+func init() {
+	var err error
+	if testKeys.rsa, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		panic(err)
+	}
+	if _, testKeys.ed, err = ed25519.GenerateKey(rand.Reader); err != nil {
+		panic(err)
+	}
+}
+
+// writePrivateKey stores key as <kid>.pem in dir
+func writePrivateKey(t *testing.T, dir, kid string, key crypto.Signer) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", kid, err)
+	}
+	writePEM(t, dir, kid, "PRIVATE KEY", der)
+}
+
+// writePublicKey stores the public half of key as <kid>.pem in dir
+func writePublicKey(t *testing.T, dir, kid string, key crypto.Signer) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", kid, err)
+	}
+	writePEM(t, dir, kid, "PUBLIC KEY", der)
+}
+
+func writePEM(t *testing.T, dir, kid, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", kid, err)
+	}
+}
+
+func initKeyring(t *testing.T, cfg config.Config) {
+	t.Helper()
+	if cfg.AppEnv == "" {
+		cfg.AppEnv = "production"
+	}
+	if err := InitJWT(cfg); err != nil {
+		t.Fatalf("InitJWT() error = %v", err)
+	}
+}
+
+// signWith signs claims for user-1 with method and key, naming kid in the header
+func signWith(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, Claims{
+		UserID: "user-1",
+		Role:   "buyer",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign %s token: %v", method.Alg(), err)
+	}
+	return signed
+}
+
+func TestKeyringSignsWithActiveKid(t *testing.T) {
+	dir := t.TempDir()
+	writePrivateKey(t, dir, "rsa-1", testKeys.rsa)
+	writePrivateKey(t, dir, "ed-1", testKeys.ed)
+
+	tests := []struct {
+		algorithm string
+		kid       string
+	}{
+		{"RS256", "rsa-1"},
+		{"EdDSA", "ed-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			initKeyring(t, config.Config{JWTAlgorithm: tt.algorithm, JWTKeysDir: dir, JWTActiveKID: tt.kid})
+
+			signed, err := GenerateToken("user-1", "buyer", 0, "session-1")
+			if err != nil {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+			parsed, _, err := jwt.NewParser().ParseUnverified(signed, &Claims{})
+			if err != nil {
+				t.Fatalf("failed to parse token: %v", err)
+			}
+			if parsed.Method.Alg() != tt.algorithm || parsed.Header["kid"] != tt.kid {
+				t.Errorf("header alg = %s, kid = %v, want %s, %s", parsed.Method.Alg(), parsed.Header["kid"], tt.algorithm, tt.kid)
+			}
+
+			claims, err := ValidateToken(signed)
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+			if claims.UserID != "user-1" {
+				t.Errorf("user_id = %q, want user-1", claims.UserID)
+			}
+		})
+	}
+}
+
+func TestKeyringVerifiesRetiredKeys(t *testing.T) {
+	dir := t.TempDir()
+	writePublicKey(t, dir, "rsa-old", testKeys.rsa)
+	writePrivateKey(t, dir, "ed-new", testKeys.ed)
+	initKeyring(t, config.Config{JWTAlgorithm: "EdDSA", JWTKeysDir: dir, JWTActiveKID: "ed-new", JWTSecret: "a-long-random-secret"})
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"retired RSA key", signWith(t, jwt.SigningMethodRS256, "rsa-old", testKeys.rsa)},
+		{"HS256 secret without kid", signWith(t, jwt.SigningMethodHS256, "", []byte("a-long-random-secret"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ValidateToken(tt.token); err != nil {
+				t.Errorf("ValidateToken() error = %v", err)
+			}
+		})
+	}
+
+	if err := InitJWT(config.Config{AppEnv: "production", JWTAlgorithm: "RS256", JWTKeysDir: dir, JWTActiveKID: "rsa-old"}); err == nil {
+		t.Error("InitJWT() accepted a public-only key as the signing key")
+	}
+}
+
+func TestValidateTokenRejectsMismatchedKeys(t *testing.T) {
+	dir := t.TempDir()
+	writePrivateKey(t, dir, "rsa-1", testKeys.rsa)
+	writePrivateKey(t, dir, "ed-1", testKeys.ed)
+	initKeyring(t, config.Config{JWTAlgorithm: "RS256", JWTKeysDir: dir, JWTActiveKID: "rsa-1", JWTSecret: "a-long-random-secret"})
+
+	der, err := x509.MarshalPKIXPublicKey(&testKeys.rsa.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		// The classic confusion attack: HMAC keyed with the published RSA key
+		{"HS256 under an RSA kid", signWith(t, jwt.SigningMethodHS256, "rsa-1", publicPEM)},
+		{"HS256 under an RSA kid keyed with the modulus", signWith(t, jwt.SigningMethodHS256, "rsa-1", testKeys.rsa.N.Bytes())},
+		{"EdDSA under an RSA kid", signWith(t, jwt.SigningMethodEdDSA, "rsa-1", testKeys.ed)},
+		{"RS256 under an EdDSA kid", signWith(t, jwt.SigningMethodRS256, "ed-1", testKeys.rsa)},
+		{"RS256 under the HS256 kid", signWith(t, jwt.SigningMethodRS256, hmacKey("a-long-random-secret").kid, testKeys.rsa)},
+		{"RS256 without kid", signWith(t, jwt.SigningMethodRS256, "", testKeys.rsa)},
+		{"unknown kid", signWith(t, jwt.SigningMethodRS256, "rsa-2", testKeys.rsa)},
+		{"alg none", signWith(t, jwt.SigningMethodNone, "rsa-1", jwt.UnsafeAllowNoneSignatureType)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ValidateToken(tt.token); err == nil {
+				t.Error("ValidateToken() accepted the token")
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	pincodePattern = regexp.MustCompile(`^[1-9][0-9]{5}$`)
+	gstinPattern   = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+)
+
+const gstinCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// ValidatePincode checks that a pincode is a six digit Indian PIN code
+func ValidatePincode(pincode string) error {
+	if !pincodePattern.MatchString(pincode) {
+		return errors.New("pincode must be a 6 digit Indian PIN code")
+	}
+	return nil
+}
+
+// NormalizeGSTIN upper-cases a GST number and validates its format and check digit
+func NormalizeGSTIN(gstin string) (string, error) {
+	gstin = strings.ToUpper(strings.TrimSpace(gstin))
+	if !gstinPattern.MatchString(gstin) {
+		return "", errors.New("gst_number must be a 15 character GSTIN")
+	}
+	if gstinCheckDigit(gstin[:14]) != gstin[14] {
+		return "", errors.New("gst_number has an invalid check digit")
+	}
+	return gstin, nil
+}
+
+// gstinCheckDigit computes the mod-36 check character over the first 14 characters
+func gstinCheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		factor := 1
+		if i%2 == 1 {
+			factor = 2
+		}
+		product := strings.IndexByte(gstinCharset, body[i]) * factor
+		sum += product/36 + product%36
+	}
+	return gstinCharset[(36-sum%36)%36]
+}
@@ -1,107 +0,0 @@
--- MySQL 8 Schema for Farmer-to-Buyer Portal MVP
-
-CREATE DATABASE IF NOT EXISTS farmer_buyer CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
-USE farmer_buyer;
-
--- Table: users
-CREATE TABLE users (
-    id CHAR(36) PRIMARY KEY,
-    phone VARCHAR(20) NOT NULL UNIQUE,
-    name VARCHAR(255) NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    role ENUM('farmer', 'buyer', 'admin') NOT NULL,
-    is_verified BOOLEAN DEFAULT FALSE,
-    is_active BOOLEAN DEFAULT TRUE,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    INDEX idx_phone (phone),
-    INDEX idx_role (role)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-
--- Table: farmer_profiles
-CREATE TABLE farmer_profiles (
-    farmer_id CHAR(36) PRIMARY KEY,
-    farm_name VARCHAR(255) NOT NULL,
-    state VARCHAR(100) NOT NULL,
-    city VARCHAR(100) NOT NULL,
-    pincode VARCHAR(10) NOT NULL,
-    address TEXT,
-    farm_size_acres DECIMAL(10, 2),
-    rating DECIMAL(3, 2) DEFAULT 0.00,
-    total_orders INT DEFAULT 0,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (farmer_id) REFERENCES users(id) ON DELETE CASCADE,
-    INDEX idx_pincode (pincode),
-    INDEX idx_farmer_id (farmer_id)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-
--- Table: buyer_profiles
-CREATE TABLE buyer_profiles (
-    buyer_id CHAR(36) PRIMARY KEY,
-    buyer_type ENUM('individual', 'restaurant', 'vendor') NOT NULL,
-    business_name VARCHAR(255),
-    gst_number VARCHAR(50),
-    state VARCHAR(100) NOT NULL,
-    city VARCHAR(100) NOT NULL,
-    pincode VARCHAR(10) NOT NULL,
-    address TEXT,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE,
-    INDEX idx_pincode (pincode),
-    INDEX idx_buyer_id (buyer_id)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-
--- Table: products
-CREATE TABLE products (
-    id CHAR(36) PRIMARY KEY,
-    farmer_id CHAR(36) NOT NULL,
-    crop_name VARCHAR(255) NOT NULL,
-    quantity DECIMAL(10, 2) NOT NULL,
-    unit VARCHAR(50) NOT NULL,
-    price_per_unit DECIMAL(10, 2) NOT NULL,
-    state VARCHAR(100) NOT NULL,
-    city VARCHAR(100) NOT NULL,
-    pincode VARCHAR(10) NOT NULL,
-    status ENUM('active', 'closed', 'sold') DEFAULT 'active',
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (farmer_id) REFERENCES users(id) ON DELETE CASCADE,
-    INDEX idx_crop_name (crop_name),
-    INDEX idx_pincode (pincode),
-    INDEX idx_farmer_id (farmer_id),
-    INDEX idx_status (status)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-
--- Table: orders
-CREATE TABLE orders (
-    id CHAR(36) PRIMARY KEY,
-    buyer_id CHAR(36) NOT NULL,
-    farmer_id CHAR(36) NOT NULL,
-    status ENUM('pending', 'accepted', 'rejected', 'shipped', 'delivered') DEFAULT 'pending',
-    delivery_mode ENUM('pickup', 'courier') NOT NULL,
-    total_amount DECIMAL(10, 2) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE,
-    FOREIGN KEY (farmer_id) REFERENCES users(id) ON DELETE CASCADE,
-    INDEX idx_buyer_id (buyer_id),
-    INDEX idx_farmer_id (farmer_id),
-    INDEX idx_status (status)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-
--- Table: order_items
-CREATE TABLE order_items (
-    id CHAR(36) PRIMARY KEY,
-    order_id CHAR(36) NOT NULL,
-    product_id CHAR(36) NOT NULL,
-    quantity DECIMAL(10, 2) NOT NULL,
-    price_per_unit DECIMAL(10, 2) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
-    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
-    INDEX idx_order_id (order_id),
-    INDEX idx_product_id (product_id)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;